@@ -0,0 +1,175 @@
+package protoiter
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Checkpoint creates a sequential iterator over the values of seq that periodically persists the position to resume from.
+//
+// After every `every` values have been consumed, that is, after the loop body has finished with them, even by breaking,
+// save is called with the key of the first value not yet consumed, so resuming from a saved key neither skips
+// nor repeats a value. To learn that key, the next value is read from seq once the loop body has finished
+// with the previous one. The position is also saved when the loop breaks, unless it was just saved.
+// When seq is exhausted, no key follows the last value: end, if not nil, is called to report the position
+// following it, e.g. the size of the stream, and is saved if known. Otherwise the values consumed since the last
+// checkpoint are not saved. If save returns an error, the iteration stops; save should record the error if needed.
+// Keys are typically the offsets yielded by [EachDelimitedIndexed], to be passed back to [ResumeAt].
+//
+// It panics if every is less than 1.
+//
+// Parameters:
+//   - seq: The sequence of position keys and values
+//   - save: The function persisting a position key
+//   - every: The number of values between checkpoints
+//   - end: The function reporting the position following the last value of seq, and whether it is known, or nil
+//
+// Returns:
+//   - An iterator sequence that yields the values of seq
+func Checkpoint[T any](seq iter.Seq2[int64, T], save func(int64) error, every int, end func() (int64, bool)) iter.Seq[T] {
+	if every < 1 {
+		panic(fmt.Sprintf("protoiter: invalid every %d", every))
+	}
+	return func(yield func(T) bool) {
+		next, stop := iter.Pull2(seq)
+		defer stop()
+		_, v, ok := next()
+		pending := 0
+		for ok {
+			more := yield(v)
+			pending++
+			var key int64
+			if key, v, ok = next(); !ok {
+				if pending > 0 && end != nil {
+					if key, known := end(); known {
+						save(key)
+					}
+				}
+				return
+			}
+			if pending == every || !more {
+				pending = 0
+				if save(key) != nil || !more {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Progress creates a sequential iterator over the values of seq that reports progress as they are consumed.
+//
+// report is called with 0 before the first value and then after the loop body has finished with each value,
+// with the number of values done so far and total. total is passed through unchanged,
+// so a negative value can signal an unknown total. Use [EachWithProgress] to take the total from a descriptor collection.
+//
+// Parameters:
+//   - seq: The sequence to iterate
+//   - total: The expected number of values
+//   - report: The function receiving progress updates, e.g. to draw a progress bar
+//
+// Returns:
+//   - An iterator sequence that yields the values of seq
+func Progress[T any](seq iter.Seq[T], total int, report func(done, total int)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		done := 0
+		report(done, total)
+		for v := range seq {
+			ok := yield(v)
+			done++
+			report(done, total)
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// EachWithProgress is like [Each] but reports progress like [Progress], with the total taken from dd.Len().
+//
+// Parameters:
+//   - dd: A collection of descriptors implementing the [Descriptors] interface
+//   - report: The function receiving progress updates
+//
+// Returns:
+//   - An iterator sequence that yields the index and descriptor for each item
+func EachWithProgress[DD Descriptors[D], D protoreflect.Descriptor](dd DD, report func(done, total int)) iter.Seq2[int, D] {
+	return func(yield func(int, D) bool) {
+		total := dd.Len()
+		report(0, total)
+		for i := range total {
+			ok := yield(i, dd.Get(i))
+			report(i+1, total)
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// ErrElementTimeout is the error yielded by [PerElementTimeout] when the next element takes too long.
+var ErrElementTimeout = errors.New("protoiter: timed out waiting for the next element")
+
+// PerElementTimeout creates a sequential iterator over the elements of seq that gives up on an element taking too long.
+//
+// seq runs on a separate goroutine, one element ahead of the consumer at most: each element is requested
+// only after the loop body has finished with the previous one, and only the time seq takes to produce it
+// counts toward d. If an element is not produced within d, a zero value and [ErrElementTimeout] are yielded
+// and the iteration stops, so a range loop over a network-backed sequence cannot hang.
+// seq is then abandoned: its goroutine exits when the pending element arrives, which is why seq must not
+// depend on running on the consumer's goroutine. It serves sequences whose elements arrive over a connection,
+// such as reflection lookups or a remote registry.
+//
+// Parameters:
+//   - seq: The sequence of elements and errors
+//   - d: The maximum time to wait for each element
+//
+// Returns:
+//   - An iterator sequence that yields the elements of seq, or [ErrElementTimeout]
+func PerElementTimeout[T any](seq iter.Seq2[T, error], d time.Duration) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		type element struct {
+			v   T
+			err error
+		}
+		elements := make(chan element)
+		next := make(chan struct{})
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			defer close(elements)
+			for v, err := range seq {
+				select {
+				case elements <- element{v, err}:
+				case <-done:
+					return
+				}
+				select {
+				case <-next:
+				case <-done:
+					return
+				}
+			}
+		}()
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		for {
+			select {
+			case e, ok := <-elements:
+				if !ok || !yield(e.v, e.err) {
+					return
+				}
+				next <- struct{}{}
+				timer.Reset(d)
+			case <-timer.C:
+				var zero T
+				yield(zero, ErrElementTimeout)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package protoiter_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func ExampleCheckpoint() {
+	stream := delimited(durationpb.New(1), durationpb.New(2), durationpb.New(3))
+	records, err := protoiter.EachDelimitedIndexed(bytes.NewReader(stream), newDuration, protoiter.DelimitedOptions{})
+	save := func(offset int64) error {
+		fmt.Println("checkpoint", offset)
+		return nil
+	}
+	end := func() (int64, bool) {
+		return int64(len(stream)), err() == nil
+	}
+	for d := range protoiter.Checkpoint(records, save, 2, end) {
+		fmt.Println(d.AsDuration())
+	}
+	// Output:
+	// 1ns
+	// 2ns
+	// checkpoint 6
+	// 3ns
+	// checkpoint 9
+}
+
+func TestCheckpoint(t *testing.T) {
+	seq := maps.All(map[int64]string{})
+	values := func(yield func(int64, string) bool) {
+		for i, s := range []string{"a", "b", "c", "d", "e"} {
+			if !yield(int64(i*10), s) {
+				return
+			}
+		}
+	}
+	var saved []int64
+	save := func(key int64) error {
+		saved = append(saved, key)
+		return nil
+	}
+	for v := range protoiter.Checkpoint(values, save, 2, nil) {
+		if v == "c" {
+			break
+		}
+	}
+	if !slices.Equal(saved, []int64{20, 30}) {
+		t.Errorf("the keys of the first unconsumed values must be saved, got %v", saved)
+	}
+
+	saved = nil
+	end := func() (int64, bool) { return 50, true }
+	for range protoiter.Checkpoint(values, save, 2, end) {
+	}
+	if !slices.Equal(saved, []int64{20, 40, 50}) {
+		t.Errorf("the end must be saved when seq is exhausted, got %v", saved)
+	}
+	saved = nil
+	for range protoiter.Checkpoint(values, save, 2, nil) {
+	}
+	if !slices.Equal(saved, []int64{20, 40}) {
+		t.Errorf("without end, the last values must not be saved, got %v", saved)
+	}
+
+	saved = nil
+	failing := func(key int64) error {
+		saved = append(saved, key)
+		return errors.New("disk full")
+	}
+	got := slices.Collect(protoiter.Checkpoint(values, failing, 1, nil))
+	if !slices.Equal(got, []string{"a"}) || !slices.Equal(saved, []int64{10}) {
+		t.Errorf("a failing save must stop the iteration: got %v, saved %v", got, saved)
+	}
+
+	for range protoiter.Checkpoint(seq, save, 1, end) {
+		t.Error("an empty sequence must not yield")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("every < 1 must panic")
+		}
+	}()
+	protoiter.Checkpoint(values, save, 0, nil)
+}
+
+func TestCheckpointResume(t *testing.T) {
+	stream := delimited(durationpb.New(1), durationpb.New(2), durationpb.New(3), durationpb.New(4))
+	r := bytes.NewReader(stream)
+	var checkpoint int64
+	save := func(offset int64) error {
+		checkpoint = offset
+		return nil
+	}
+	records, errFn := protoiter.EachDelimitedIndexed(r, newDuration, protoiter.DelimitedOptions{})
+	end := func() (int64, bool) {
+		return int64(len(stream)), errFn() == nil
+	}
+	var got []time.Duration
+	for d := range protoiter.Checkpoint(records, save, 1, end) {
+		got = append(got, d.AsDuration())
+		if len(got) == 2 {
+			break // as if the process had crashed after handling the second record
+		}
+	}
+	records, errFn = protoiter.ResumeAt(r, checkpoint, newDuration, protoiter.DelimitedOptions{})
+	for d := range protoiter.Checkpoint(records, save, 1, end) {
+		got = append(got, d.AsDuration())
+	}
+	if want := []time.Duration{1, 2, 3, 4}; !slices.Equal(got, want) || errFn() != nil {
+		t.Errorf("each record must be processed once: got %v, want %v, error %v", got, want, errFn())
+	}
+	if checkpoint != int64(len(stream)) {
+		t.Errorf("the last checkpoint must be the end of the stream, got %d", checkpoint)
+	}
+}
+
+func ExampleEachWithProgress() {
+	fields := (&durationpb.Duration{}).ProtoReflect().Descriptor().Fields()
+	report := func(done, total int) {
+		fmt.Printf("%d/%d\n", done, total)
+	}
+	for _, fd := range protoiter.EachWithProgress(fields, report) {
+		fmt.Println(fd.Name())
+	}
+	// Output:
+	// 0/2
+	// seconds
+	// 1/2
+	// nanos
+	// 2/2
+}
+
+func TestProgress(t *testing.T) {
+	var reports []string
+	report := func(done, total int) {
+		reports = append(reports, fmt.Sprint(done, "/", total))
+	}
+	for v := range protoiter.Progress(slices.Values([]string{"a", "b", "c"}), -1, report) {
+		if v == "b" {
+			break
+		}
+	}
+	if want := []string{"0/-1", "1/-1", "2/-1"}; !slices.Equal(reports, want) {
+		t.Errorf("got %v want %v", reports, want)
+	}
+	reports = nil
+	fields := (&durationpb.Duration{}).ProtoReflect().Descriptor().Fields()
+	for range protoiter.EachWithProgress(fields, report) {
+		break
+	}
+	if want := []string{"0/2", "1/2"}; !slices.Equal(reports, want) {
+		t.Errorf("got %v want %v", reports, want)
+	}
+}
+
+func ExamplePerElementTimeout() {
+	slow := func(yield func(string, error) bool) {
+		if !yield("fast", nil) {
+			return
+		}
+		time.Sleep(200 * time.Millisecond)
+		yield("slow", nil)
+	}
+	for v, err := range protoiter.PerElementTimeout(slow, 10*time.Millisecond) {
+		fmt.Printf("%q %v\n", v, err)
+	}
+	// Output:
+	// "fast" <nil>
+	// "" protoiter: timed out waiting for the next element
+}
+
+func TestPerElementTimeout(t *testing.T) {
+	seq := func(yield func(int, error) bool) {
+		for i := range 3 {
+			if !yield(i, nil) {
+				return
+			}
+		}
+		yield(-1, errors.New("broken"))
+	}
+	var got []int
+	var errs []error
+	for v, err := range protoiter.PerElementTimeout(seq, time.Second) {
+		// A slow loop body does not count toward the timeout.
+		time.Sleep(5 * time.Millisecond)
+		got = append(got, v)
+		errs = append(errs, err)
+	}
+	if want := []int{0, 1, 2, -1}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	if errs[3] == nil || errors.Is(errs[3], protoiter.ErrElementTimeout) {
+		t.Errorf("unexpected errors %v", errs)
+	}
+
+	got, errs = nil, nil
+	for v, err := range protoiter.PerElementTimeout(seq, 10*time.Millisecond) {
+		// A loop body several times slower than d must not trigger the timeout.
+		time.Sleep(50 * time.Millisecond)
+		got = append(got, v)
+		errs = append(errs, err)
+	}
+	if want := []int{0, 1, 2, -1}; !slices.Equal(got, want) || slices.ContainsFunc(errs, func(err error) bool {
+		return errors.Is(err, protoiter.ErrElementTimeout)
+	}) {
+		t.Errorf("a slow loop body must not time out: got %v, errors %v", got, errs)
+	}
+
+	n := 0
+	for range protoiter.PerElementTimeout(seq, 2*time.Millisecond) {
+		n++
+		time.Sleep(10 * time.Millisecond)
+		break
+	}
+	if n != 1 {
+		t.Errorf("iteration must stop after break, got %d", n)
+	}
+}
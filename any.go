@@ -0,0 +1,211 @@
+package protoiter
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// EachAnyIn creates a sequential iterator over the elements of a repeated google.protobuf.Any field unpacked into a concrete type.
+//
+// Each element is unmarshaled into a message created by newM. If the type URL of an element does not name
+// the message type of newM, or the payload cannot be unmarshaled, a zero M and an error naming the element index
+// are yielded and iteration continues. The list may come from a generated or a dynamic message, and M may be
+// an interface type or *dynamicpb.Message.
+//
+// Parameters:
+//   - list: The value of a repeated google.protobuf.Any field
+//   - newM: A function returning a new empty message for each element
+//
+// Returns:
+//   - An iterator sequence that yields each unpacked message, or an error
+func EachAnyIn[M proto.Message](list protoreflect.List, newM func() M) iter.Seq2[M, error] {
+	return func(yield func(M, error) bool) {
+		var zero M
+		want := newM().ProtoReflect().Descriptor().FullName()
+		for i := range list.Len() {
+			url, value := anyFields(list.Get(i).Message())
+			if name := typeURLName(url); name != want {
+				if !yield(zero, fmt.Errorf("protoiter: element %d: type %q is not %v", i, url, want)) {
+					return
+				}
+				continue
+			}
+			m := newM()
+			if err := proto.Unmarshal(value, m); err != nil {
+				if !yield(zero, fmt.Errorf("protoiter: element %d: %w", i, err)) {
+					return
+				}
+				continue
+			}
+			if !yield(m, nil) {
+				return
+			}
+		}
+	}
+}
+
+// EachAnyResolved creates a sequential iterator over the elements of a repeated google.protobuf.Any field
+// unpacked into the types found by a resolver.
+//
+// If the type URL of an element cannot be resolved or the payload cannot be unmarshaled,
+// a nil message and an error naming the element index are yielded and iteration continues.
+//
+// Parameters:
+//   - list: The value of a repeated google.protobuf.Any field
+//   - resolver: The resolver of type URLs, or nil for [protoregistry.GlobalTypes]
+//
+// Returns:
+//   - An iterator sequence that yields each unpacked message, or an error
+func EachAnyResolved(list protoreflect.List, resolver protoregistry.MessageTypeResolver) iter.Seq2[proto.Message, error] {
+	if resolver == nil {
+		resolver = protoregistry.GlobalTypes
+	}
+	return func(yield func(proto.Message, error) bool) {
+		for i := range list.Len() {
+			m, err := unpackAny(list.Get(i).Message(), resolver)
+			if err != nil {
+				err = fmt.Errorf("protoiter: element %d: %w", i, err)
+			}
+			if !yield(m, err) {
+				return
+			}
+		}
+	}
+}
+
+// unpackAny unmarshals the payload of a google.protobuf.Any message into the type found by resolver.
+func unpackAny(a protoreflect.Message, resolver protoregistry.MessageTypeResolver) (proto.Message, error) {
+	url, value := anyFields(a)
+	mt, err := resolver.FindMessageByURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("type %q: %w", url, err)
+	}
+	m := mt.New().Interface()
+	if err := proto.Unmarshal(value, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// anyFields returns the type URL and the payload of a google.protobuf.Any message, generated or dynamic.
+func anyFields(a protoreflect.Message) (url string, value []byte) {
+	fields := a.Descriptor().Fields()
+	return a.Get(fields.ByNumber(1)).String(), a.Get(fields.ByNumber(2)).Bytes()
+}
+
+// typeURLName returns the message name of a type URL, the part after the last "/".
+func typeURLName(url string) protoreflect.FullName {
+	return protoreflect.FullName(url[strings.LastIndexByte(url, '/')+1:])
+}
+
+// EachTypeURL creates a sequential iterator over the distinct type URLs of the google.protobuf.Any messages in a message tree.
+//
+// The iterator descends into every populated message value, including list elements and map values,
+// and yields each type URL the first time it is found. The payloads of the Any messages are not unpacked,
+// so type URLs of Any messages packed inside another Any are not reported.
+// This lets a server check that it can resolve every embedded payload before processing a request.
+//
+// Parameters:
+//   - message: The protocol buffer message to scan
+//
+// Returns:
+//   - An iterator sequence that yields each distinct type URL
+func EachTypeURL(message protoreflect.Message) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		eachTypeURL(message, make(map[string]bool), yield)
+	}
+}
+
+func eachTypeURL(message protoreflect.Message, seen map[string]bool, yield func(string) bool) bool {
+	return eachAny(message, func(a protoreflect.Message) bool {
+		url, _ := anyFields(a)
+		if seen[url] {
+			return true
+		}
+		seen[url] = true
+		return yield(url)
+	})
+}
+
+// eachAny calls yield for each google.protobuf.Any message in a message tree, including message itself,
+// without descending into the Any messages.
+func eachAny(message protoreflect.Message, yield func(protoreflect.Message) bool) bool {
+	if message.Descriptor().FullName() == anyName {
+		return yield(message)
+	}
+	ok := true
+	message.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				ok = eachAny(v.Message(), yield)
+				return ok
+			})
+		case fd.IsList():
+			if fd.Message() == nil {
+				return true
+			}
+			list := v.List()
+			for i := 0; ok && i < list.Len(); i++ {
+				ok = eachAny(list.Get(i).Message(), yield)
+			}
+		case fd.Message() != nil:
+			ok = eachAny(v.Message(), yield)
+		}
+		return ok
+	})
+	return ok
+}
+
+// MaxUnpackDepth is the number of nested google.protobuf.Any levels [UnpackDeep] unpacks.
+const MaxUnpackDepth = 16
+
+// UnpackDeep creates a sequential iterator over the message packed in a google.protobuf.Any and,
+// recursively, over the messages packed in the Any messages it contains.
+//
+// The unpacked messages are yielded depth-first: each message comes before the messages packed inside it,
+// which are found anywhere in its tree, as by [EachTypeURL]. Envelopes nested in envelopes are thus unpacked
+// down to the innermost payload. If a payload cannot be resolved or unmarshaled, or the Any messages are nested
+// more than [MaxUnpackDepth] levels deep, a nil message and an error are yielded in its place and its contents are skipped.
+//
+// Parameters:
+//   - a: The Any message to unpack
+//   - resolver: The resolver of type URLs, or nil for [protoregistry.GlobalTypes]
+//
+// Returns:
+//   - An iterator sequence that yields each unpacked message, or an error
+func UnpackDeep(a *anypb.Any, resolver protoregistry.MessageTypeResolver) iter.Seq2[proto.Message, error] {
+	if resolver == nil {
+		resolver = protoregistry.GlobalTypes
+	}
+	return func(yield func(proto.Message, error) bool) {
+		unpackDeep(a.ProtoReflect(), resolver, 1, yield)
+	}
+}
+
+func unpackDeep(a protoreflect.Message, resolver protoregistry.MessageTypeResolver, depth int, yield func(proto.Message, error) bool) bool {
+	if depth > MaxUnpackDepth {
+		return yield(nil, fmt.Errorf("protoiter: google.protobuf.Any nested more than %d levels deep", MaxUnpackDepth))
+	}
+	m, err := unpackAny(a, resolver)
+	if err != nil {
+		return yield(nil, fmt.Errorf("protoiter: %w", err))
+	}
+	if !yield(m, nil) {
+		return false
+	}
+	return eachAny(m.ProtoReflect(), func(a protoreflect.Message) bool {
+		return unpackDeep(a, resolver, depth+1, yield)
+	})
+}
+
+const anyName protoreflect.FullName = "google.protobuf.Any"
@@ -0,0 +1,167 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const envelopeProto = `
+	name: "envelope.proto"
+	package: "test"
+	dependency: "google/protobuf/any.proto"
+	message_type {
+		name: "Envelope"
+		field { name: "items" number: 1 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".google.protobuf.Any" }
+		field { name: "payload" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Any" }
+	}
+`
+
+// newEnvelope returns a dynamic test.Envelope holding the messages as Any items.
+func newEnvelope(items ...proto.Message) protoreflect.Message {
+	md := results.Must1(newFiles(envelopeProto).FindDescriptorByName("test.Envelope")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	list := m.Mutable(md.Fields().ByName("items")).List()
+	for _, item := range items {
+		list.Append(protoreflect.ValueOfMessage(results.Must1(anypb.New(item)).ProtoReflect()))
+	}
+	return m
+}
+
+func ExampleEachAnyIn() {
+	m := newEnvelope(durationpb.New(1), timestamppb.New(time.Unix(2, 0)), durationpb.New(3))
+	items := m.Get(m.Descriptor().Fields().ByName("items")).List()
+	for d, err := range protoiter.EachAnyIn(items, newDuration) {
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(d.AsDuration())
+	}
+	// Output:
+	// 1ns
+	// protoiter: element 1: type "type.googleapis.com/google.protobuf.Timestamp" is not google.protobuf.Duration
+	// 3ns
+}
+
+func TestEachAnyIn(t *testing.T) {
+	m := newEnvelope(durationpb.New(1), timestamppb.New(time.Unix(2, 0)))
+	items := m.Get(m.Descriptor().Fields().ByName("items")).List()
+	md := (&durationpb.Duration{}).ProtoReflect().Descriptor()
+	var got []string
+	for d, err := range protoiter.EachAnyIn(items, func() *dynamicpb.Message { return dynamicpb.NewMessage(md) }) {
+		if err != nil {
+			got = append(got, "error")
+			continue
+		}
+		got = append(got, fmt.Sprint(d.Get(md.Fields().ByName("nanos"))))
+	}
+	if fmt.Sprint(got) != "[1 error]" {
+		t.Errorf("dynamic messages must be unpacked, got %v", got)
+	}
+
+	newM := func() proto.Message { return &durationpb.Duration{} }
+	for d, err := range protoiter.EachAnyIn(items, newM) {
+		if err == nil && d.(*durationpb.Duration).AsDuration() != 1 {
+			t.Errorf("unexpected message %v", d)
+		}
+		if err != nil && d != nil {
+			t.Errorf("a zero message must be yielded with an error, got %v", d)
+		}
+	}
+}
+
+func TestEachAnyResolved(t *testing.T) {
+	m := newEnvelope(durationpb.New(1), timestamppb.New(time.Unix(2, 0)))
+	items := m.Get(m.Descriptor().Fields().ByName("items")).List()
+	var names []string
+	for m, err := range protoiter.EachAnyResolved(items, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, string(m.ProtoReflect().Descriptor().Name()))
+	}
+	if fmt.Sprint(names) != "[Duration Timestamp]" {
+		t.Errorf("unexpected messages %v", names)
+	}
+	for m, err := range protoiter.EachAnyResolved(items, new(protoregistry.Types)) {
+		if m != nil || err == nil {
+			t.Error("an unknown type must be an error")
+		}
+	}
+}
+
+func TestEachTypeURL(t *testing.T) {
+	m := newEnvelope(durationpb.New(1), timestamppb.New(time.Unix(2, 0)), durationpb.New(3))
+	m.Set(m.Descriptor().Fields().ByName("payload"), protoreflect.ValueOfMessage(results.Must1(anypb.New(&emptypb.Empty{})).ProtoReflect()))
+	got := slices.Collect(protoiter.EachTypeURL(m))
+	want := []string{
+		"type.googleapis.com/google.protobuf.Duration",
+		"type.googleapis.com/google.protobuf.Timestamp",
+		"type.googleapis.com/google.protobuf.Empty",
+	}
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+	for range protoiter.EachTypeURL(m) {
+		break
+	}
+}
+
+func ExampleUnpackDeep() {
+	inner := newEnvelope(durationpb.New(1), timestamppb.New(time.Unix(2, 0)))
+	outer := results.Must1(anypb.New(newEnvelope(inner.Interface(), durationpb.New(3)).Interface()))
+	local := new(protoregistry.Types)
+	results.Must(local.RegisterMessage(dynamicpb.NewMessageType(inner.Descriptor())))
+	for m, err := range protoiter.UnpackDeep(outer, protoiter.TypesChain{local, protoregistry.GlobalTypes}) {
+		fmt.Println(m.ProtoReflect().Descriptor().FullName(), err)
+	}
+	// Output:
+	// test.Envelope <nil>
+	// test.Envelope <nil>
+	// google.protobuf.Duration <nil>
+	// google.protobuf.Timestamp <nil>
+	// google.protobuf.Duration <nil>
+}
+
+func TestUnpackDeep(t *testing.T) {
+	var m proto.Message = durationpb.New(1)
+	for range protoiter.MaxUnpackDepth + 1 {
+		m = results.Must1(anypb.New(m))
+	}
+	var got []string
+	for m, err := range protoiter.UnpackDeep(m.(*anypb.Any), nil) {
+		if err != nil {
+			got = append(got, "error")
+			continue
+		}
+		got = append(got, string(m.ProtoReflect().Descriptor().FullName()))
+	}
+	if len(got) != protoiter.MaxUnpackDepth+1 || got[len(got)-2] != "google.protobuf.Any" || got[len(got)-1] != "error" {
+		t.Errorf("unexpected %v", got)
+	}
+
+	unresolved := results.Must1(anypb.New(newEnvelope().Interface()))
+	for m, err := range protoiter.UnpackDeep(unresolved, nil) {
+		if m != nil || err == nil {
+			t.Errorf("got %v, %v; want an error", m, err)
+		}
+	}
+	for range protoiter.UnpackDeep(results.Must1(anypb.New(m)), nil) {
+		break
+	}
+}
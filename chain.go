@@ -0,0 +1,191 @@
+package protoiter
+
+import (
+	"errors"
+	"iter"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// TypesChain is a list of type registries consulted in order.
+//
+// A lookup returns the result of the first registry that does not report [protoregistry.NotFound],
+// so generated types in [protoregistry.GlobalTypes] can be mixed with dynamically loaded ones.
+// A type found in an earlier registry shadows a type with the same name, or the same extendee and number,
+// in a later one.
+//
+// TypesChain implements [Types], [protoregistry.MessageTypeResolver] and [protoregistry.ExtensionTypeResolver],
+// so it can be passed to [EachExtensionByMessage], [EachAnyResolved] or the protobuf encoding options.
+type TypesChain []*protoregistry.Types
+
+var (
+	_ Types                               = TypesChain(nil)
+	_ protoregistry.MessageTypeResolver   = TypesChain(nil)
+	_ protoregistry.ExtensionTypeResolver = TypesChain(nil)
+)
+
+// find returns the first result of lookup that is not NotFound, along with the index of the registry.
+func find[T any](c TypesChain, lookup func(*protoregistry.Types) (T, error)) (T, int, error) {
+	for i, types := range c {
+		v, err := lookup(types)
+		if errors.Is(err, protoregistry.NotFound) {
+			continue
+		}
+		return v, i, err
+	}
+	var zero T
+	return zero, -1, protoregistry.NotFound
+}
+
+// FindEnumByName looks up an enum by its full name.
+func (c TypesChain) FindEnumByName(name protoreflect.FullName) (protoreflect.EnumType, error) {
+	et, _, err := find(c, func(t *protoregistry.Types) (protoreflect.EnumType, error) { return t.FindEnumByName(name) })
+	return et, err
+}
+
+// FindMessageByName looks up a message by its full name.
+func (c TypesChain) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	mt, _, err := find(c, func(t *protoregistry.Types) (protoreflect.MessageType, error) { return t.FindMessageByName(name) })
+	return mt, err
+}
+
+// FindMessageByURL looks up a message by a URL identifier.
+func (c TypesChain) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	mt, _, err := find(c, func(t *protoregistry.Types) (protoreflect.MessageType, error) { return t.FindMessageByURL(url) })
+	return mt, err
+}
+
+// FindExtensionByName looks up an extension field by the field's full name.
+func (c TypesChain) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	xt, _, err := find(c, func(t *protoregistry.Types) (protoreflect.ExtensionType, error) { return t.FindExtensionByName(field) })
+	return xt, err
+}
+
+// FindExtensionByNumber looks up an extension field by the field number within some parent message.
+func (c TypesChain) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	xt, _, err := find(c, func(t *protoregistry.Types) (protoreflect.ExtensionType, error) {
+		return t.FindExtensionByNumber(message, field)
+	})
+	return xt, err
+}
+
+// RangeEnums iterates over the enums of all registries in order, skipping shadowed ones, while f returns true.
+func (c TypesChain) RangeEnums(f func(protoreflect.EnumType) bool) {
+	seen := make(map[protoreflect.FullName]bool)
+	for _, types := range c {
+		ok := true
+		types.RangeEnums(func(et protoreflect.EnumType) bool {
+			name := et.Descriptor().FullName()
+			if seen[name] {
+				return true
+			}
+			seen[name] = true
+			ok = f(et)
+			return ok
+		})
+		if !ok {
+			return
+		}
+	}
+}
+
+// RangeMessages iterates over the messages of all registries in order, skipping shadowed ones, while f returns true.
+func (c TypesChain) RangeMessages(f func(protoreflect.MessageType) bool) {
+	seen := make(map[protoreflect.FullName]bool)
+	for _, types := range c {
+		ok := true
+		types.RangeMessages(func(mt protoreflect.MessageType) bool {
+			name := mt.Descriptor().FullName()
+			if seen[name] {
+				return true
+			}
+			seen[name] = true
+			ok = f(mt)
+			return ok
+		})
+		if !ok {
+			return
+		}
+	}
+}
+
+// RangeExtensions iterates over the extensions of all registries in order, skipping shadowed ones, while f returns true.
+func (c TypesChain) RangeExtensions(f func(protoreflect.ExtensionType) bool) {
+	c.rangeExtensions(func(types *protoregistry.Types, f func(protoreflect.ExtensionType) bool) {
+		types.RangeExtensions(f)
+	}, func(_ int, xt protoreflect.ExtensionType) bool {
+		return f(xt)
+	})
+}
+
+// RangeExtensionsByMessage iterates over the extensions of a message in all registries in order,
+// skipping shadowed ones, while f returns true.
+func (c TypesChain) RangeExtensionsByMessage(message protoreflect.FullName, f func(protoreflect.ExtensionType) bool) {
+	for _, xt := range c.EachExtensionByMessage(message) {
+		if !f(xt) {
+			return
+		}
+	}
+}
+
+// EachExtensionByMessage creates a sequential iterator over the extensions of a message in all registries,
+// paired with the index of the registry that provides each one.
+//
+// Parameters:
+//   - message: The full name of the message to filter extension types
+//
+// Returns:
+//   - An iterator sequence that yields the registry index and each extension type
+func (c TypesChain) EachExtensionByMessage(message protoreflect.FullName) iter.Seq2[int, protoreflect.ExtensionType] {
+	return func(yield func(int, protoreflect.ExtensionType) bool) {
+		c.rangeExtensions(func(types *protoregistry.Types, f func(protoreflect.ExtensionType) bool) {
+			types.RangeExtensionsByMessage(message, f)
+		}, yield)
+	}
+}
+
+func (c TypesChain) rangeExtensions(each func(*protoregistry.Types, func(protoreflect.ExtensionType) bool), yield func(int, protoreflect.ExtensionType) bool) {
+	type key struct {
+		message protoreflect.FullName
+		number  protoreflect.FieldNumber
+	}
+	seen := make(map[key]bool)
+	for i, types := range c {
+		ok := true
+		each(types, func(xt protoreflect.ExtensionType) bool {
+			xd := xt.TypeDescriptor()
+			k := key{xd.ContainingMessage().FullName(), xd.Number()}
+			if seen[k] {
+				return true
+			}
+			seen[k] = true
+			ok = yield(i, xt)
+			return ok
+		})
+		if !ok {
+			return
+		}
+	}
+}
+
+// EachMessageByURL creates a sequential iterator that resolves each type URL against the registries in order.
+//
+// Each URL is paired with the index of the registry that resolved it and the message type,
+// or with -1 and nil if no registry knows it. It combines with [EachTypeURL] to check embedded payloads.
+//
+// Parameters:
+//   - urls: The type URLs to resolve
+//
+// Returns:
+//   - An iterator sequence that yields the registry index and message type of each URL
+func (c TypesChain) EachMessageByURL(urls iter.Seq[string]) iter.Seq2[int, protoreflect.MessageType] {
+	return func(yield func(int, protoreflect.MessageType) bool) {
+		for url := range urls {
+			mt, i, _ := find(c, func(t *protoregistry.Types) (protoreflect.MessageType, error) { return t.FindMessageByURL(url) })
+			if !yield(i, mt) {
+				return
+			}
+		}
+	}
+}
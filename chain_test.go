@@ -0,0 +1,79 @@
+package protoiter_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+func ExampleTypesChain() {
+	files := newFiles(extProto)
+	chain := protoiter.TypesChain{
+		newExtensionTypes(files, "test.a"),
+		newExtensionTypes(files, "test.a", "test.b"),
+	}
+	for i, xt := range chain.EachExtensionByMessage("test.Base") {
+		fmt.Println(i, xt.TypeDescriptor().FullName())
+	}
+	// Output:
+	// 0 test.a
+	// 1 test.b
+}
+
+func TestTypesChain(t *testing.T) {
+	files := newFiles(extProto)
+	local := newExtensionTypes(files, "test.a", "test.b")
+	chain := protoiter.TypesChain{protoregistry.GlobalTypes, local}
+
+	xt, err := chain.FindExtensionByNumber("test.Base", 101)
+	if err != nil || xt.TypeDescriptor().FullName() != "test.b" {
+		t.Errorf("FindExtensionByNumber = %v, %v", xt, err)
+	}
+	if _, err := chain.FindMessageByName("test.Missing"); !errors.Is(err, protoregistry.NotFound) {
+		t.Errorf("FindMessageByName must report NotFound, got %v", err)
+	}
+	if mt, err := chain.FindMessageByName("google.protobuf.Any"); err != nil || mt == nil {
+		t.Errorf("FindMessageByName = %v, %v", mt, err)
+	}
+
+	urls := slices.Values([]string{"type.googleapis.com/google.protobuf.Duration", "type.googleapis.com/test.Missing"})
+	var found []int
+	for i, mt := range chain.EachMessageByURL(urls) {
+		if (i < 0) != (mt == nil) {
+			t.Errorf("index %d does not agree with %v", i, mt)
+		}
+		found = append(found, i)
+	}
+	if !slices.Equal(found, []int{0, -1}) {
+		t.Errorf("EachMessageByURL found %v", found)
+	}
+
+	var names []protoreflect.FullName
+	for xt := range protoiter.EachExtensionByMessage(chain, "test.Base") {
+		names = append(names, xt.TypeDescriptor().FullName())
+	}
+	slices.Sort(names)
+	if want := []protoreflect.FullName{"test.a", "test.b"}; !slices.Equal(names, want) {
+		t.Errorf("got %v want %v", names, want)
+	}
+
+	shadowed := protoiter.TypesChain{local, local}
+	n := 0
+	for range protoiter.EachExtension(shadowed) {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("shadowed extensions must be skipped, got %d", n)
+	}
+	for range protoiter.EachMessage(chain) {
+		break
+	}
+	for range protoiter.EachEnum(chain) {
+		break
+	}
+}
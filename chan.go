@@ -0,0 +1,143 @@
+package protoiter
+
+import (
+	"context"
+	"iter"
+	"sync/atomic"
+)
+
+// ChanPolicy decides what [ToChan] does when the channel buffer is full.
+type ChanPolicy int
+
+const (
+	// Block waits until the consumer receives, propagating backpressure to the sequence.
+	Block ChanPolicy = iota
+
+	// DropNewest discards the element being sent.
+	DropNewest
+
+	// DropOldest discards the oldest buffered element to make room for the element being sent.
+	DropOldest
+)
+
+// ChanOptions configures [ToChan].
+//
+// The zero value creates an unbuffered channel that blocks.
+type ChanOptions struct {
+	// Buffer is the capacity of the channel.
+	Buffer int
+
+	// Policy is applied when the buffer is full.
+	// Dropping policies require a positive Buffer, otherwise every element without a waiting receiver is dropped.
+	Policy ChanPolicy
+}
+
+// ChanStats counts the elements handled by [ToChan]. It is safe to read while the producer runs.
+type ChanStats struct {
+	sent, dropped atomic.Int64
+}
+
+// Sent reports the number of elements delivered to the channel.
+// It only increases: elements later evicted by [DropOldest] remain counted here and are counted in Dropped as well.
+func (s *ChanStats) Sent() int64 {
+	return s.sent.Load()
+}
+
+// Dropped reports the number of elements discarded by a dropping policy.
+func (s *ChanStats) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// ToChan starts a goroutine that sends the elements of seq to the returned channel.
+//
+// The channel is closed when seq is exhausted or ctx is done; in the latter case the iteration of seq stops.
+// When the buffer is full, the behavior follows opts.Policy, and the returned stats count delivered and dropped elements.
+//
+// Parameters:
+//   - ctx: The context whose cancellation stops the producer
+//   - seq: The sequence to drain
+//   - opts: The buffering and backpressure options
+//
+// Returns:
+//   - The receive-only channel, and the counters of the producer
+func ToChan[T any](ctx context.Context, seq iter.Seq[T], opts ChanOptions) (<-chan T, *ChanStats) {
+	ch := make(chan T, opts.Buffer)
+	stats := new(ChanStats)
+	go func() {
+		defer close(ch)
+		for v := range seq {
+			if !send(ctx, ch, v, opts.Policy, stats) {
+				return
+			}
+		}
+	}()
+	return ch, stats
+}
+
+// send delivers v according to policy and reports false if ctx is done.
+func send[T any](ctx context.Context, ch chan T, v T, policy ChanPolicy, stats *ChanStats) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	switch policy {
+	case DropNewest:
+		select {
+		case ch <- v:
+			stats.sent.Add(1)
+		default:
+			stats.dropped.Add(1)
+		}
+		return true
+	case DropOldest:
+		for {
+			select {
+			case ch <- v:
+				stats.sent.Add(1)
+				return true
+			default:
+			}
+			select {
+			case <-ch:
+				stats.dropped.Add(1)
+			default:
+				if cap(ch) == 0 {
+					stats.dropped.Add(1)
+					return true
+				}
+			}
+		}
+	default:
+		select {
+		case ch <- v:
+			stats.sent.Add(1)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// FromChan creates a sequential iterator over the values received from a channel.
+//
+// The iteration ends when the channel is closed or ctx is done.
+//
+// Parameters:
+//   - ctx: The context whose cancellation ends the iteration
+//   - ch: The channel to receive from
+//
+// Returns:
+//   - An iterator sequence that yields each received value
+func FromChan[T any](ctx context.Context, ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok || !yield(v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
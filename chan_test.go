@@ -0,0 +1,68 @@
+package protoiter_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+)
+
+func ExampleToChan() {
+	ctx := context.Background()
+	ch, stats := protoiter.ToChan(ctx, slices.Values([]string{"a", "b", "c"}), protoiter.ChanOptions{Buffer: 1})
+	for v := range protoiter.FromChan(ctx, ch) {
+		fmt.Println(v)
+	}
+	fmt.Println(stats.Sent(), stats.Dropped())
+	// Output:
+	// a
+	// b
+	// c
+	// 3 0
+}
+
+// settle waits until the producer has sent and dropped the given numbers of elements.
+func settle(stats *protoiter.ChanStats, sent, dropped int64) {
+	for stats.Sent() < sent || stats.Dropped() < dropped {
+		runtime.Gosched()
+	}
+}
+
+func TestToChan(t *testing.T) {
+	ctx := context.Background()
+	numbers := slices.Values([]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+	ch, stats := protoiter.ToChan(ctx, numbers, protoiter.ChanOptions{Buffer: 2, Policy: protoiter.DropNewest})
+	settle(stats, 2, 8)
+	if got := slices.Collect(protoiter.FromChan(ctx, ch)); !slices.Equal(got, []int{0, 1}) {
+		t.Errorf("DropNewest kept %v", got)
+	}
+	if stats.Sent() != 2 || stats.Dropped() != 8 {
+		t.Errorf("DropNewest counted %d sent, %d dropped", stats.Sent(), stats.Dropped())
+	}
+
+	ch, stats = protoiter.ToChan(ctx, numbers, protoiter.ChanOptions{Buffer: 2, Policy: protoiter.DropOldest})
+	settle(stats, 10, 8)
+	if got := slices.Collect(protoiter.FromChan(ctx, ch)); !slices.Equal(got, []int{8, 9}) {
+		t.Errorf("DropOldest kept %v", got)
+	}
+	// Every element was sent; the evicted ones count as dropped, too.
+	if stats.Sent() != 10 || stats.Dropped() != 8 {
+		t.Errorf("DropOldest counted %d sent, %d dropped", stats.Sent(), stats.Dropped())
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	ch, _ = protoiter.ToChan(cancelled, numbers, protoiter.ChanOptions{})
+	if v := <-ch; v != 0 {
+		t.Errorf("first value %d", v)
+	}
+	cancel()
+	for range ch {
+	}
+	for range protoiter.FromChan(cancelled, make(chan int)) {
+		t.Error("a cancelled context must end the iteration")
+	}
+}
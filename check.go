@@ -0,0 +1,148 @@
+package protoiter
+
+import (
+	"iter"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachOrphanExtension creates a sequential iterator over the extension types whose extendee message is not present in a files registry.
+//
+// Such extensions typically come from descriptor sets assembled from several sources,
+// where a type registry was populated without the files declaring the extended messages.
+// Because the files registry is scanned before the first element is yielded, the iteration is not lazy.
+//
+// Parameters:
+//   - types: A Types implementation providing access to extension types
+//   - files: A Files implementation expected to declare every extended message
+//
+// Returns:
+//   - An iterator sequence that yields each orphan extension type
+func EachOrphanExtension(types Types, files Files) iter.Seq[protoreflect.ExtensionType] {
+	return func(yield func(protoreflect.ExtensionType) bool) {
+		messages := make(map[protoreflect.FullName]bool)
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			if md, ok := d.(protoreflect.MessageDescriptor); ok {
+				messages[md.FullName()] = true
+			}
+			return true
+		})
+		types.RangeExtensions(func(xt protoreflect.ExtensionType) bool {
+			if messages[xt.TypeDescriptor().ContainingMessage().FullName()] {
+				return true
+			}
+			return yield(xt)
+		})
+	}
+}
+
+// EachPlaceholder creates a sequential iterator over the placeholder descriptors referenced from a registry.
+//
+// Placeholders stand in for imports and types that could not be resolved when the files were built,
+// for example with [google.golang.org/protobuf/reflect/protodesc.FileOptions.AllowUnresolvable].
+// They are found among file imports, field and extension types, extendees, and method requests and responses.
+// Each placeholder is yielded once, identified by its full name (its path for files).
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields each distinct placeholder descriptor
+func EachPlaceholder(files Files) iter.Seq[protoreflect.Descriptor] {
+	return func(yield func(protoreflect.Descriptor) bool) {
+		seen := make(map[string]bool)
+		check := func(d protoreflect.Descriptor) bool {
+			if d == nil || !d.IsPlaceholder() {
+				return true
+			}
+			key := string(d.FullName())
+			if fd, ok := d.(protoreflect.FileDescriptor); ok {
+				key = "file:" + fd.Path()
+			}
+			if seen[key] {
+				return true
+			}
+			seen[key] = true
+			return yield(d)
+		}
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			switch d := d.(type) {
+			case protoreflect.FileDescriptor:
+				imports := d.Imports()
+				for i := range imports.Len() {
+					if !check(imports.Get(i).FileDescriptor) {
+						return false
+					}
+				}
+			case protoreflect.FieldDescriptor:
+				if d.Message() != nil && !check(d.Message()) {
+					return false
+				}
+				if d.Enum() != nil && !check(d.Enum()) {
+					return false
+				}
+				if d.IsExtension() && !check(d.ContainingMessage()) {
+					return false
+				}
+			case protoreflect.MethodDescriptor:
+				return check(d.Input()) && check(d.Output())
+			}
+			return true
+		})
+	}
+}
+
+// EachNameConflict creates a sequential iterator over the full names declared in two registries with different definitions.
+//
+// Each declaration is compared by its own descriptor content, as converted by [protodesc]:
+// a message by its fields, oneofs, ranges, reserved names and options, but not by its nested declarations,
+// which are compared on their own; an enum by its values; a service by its methods.
+// Declarations of different kinds with the same name conflict as well. Source locations are not compared.
+// This validates a plugin's descriptor set against the process-global registry before merging them.
+// Because b is scanned before the first element is yielded, the iteration is not lazy.
+//
+// Parameters:
+//   - a: A Files implementation, e.g. the descriptors to be merged
+//   - b: A Files implementation, e.g. [google.golang.org/protobuf/reflect/protoregistry.GlobalFiles]
+//
+// Returns:
+//   - An iterator sequence that yields the declaration in a and the one in b of each conflicting name
+func EachNameConflict(a, b Files) iter.Seq2[protoreflect.Descriptor, protoreflect.Descriptor] {
+	return func(yield func(protoreflect.Descriptor, protoreflect.Descriptor) bool) {
+		declared := make(map[protoreflect.FullName]protoreflect.Descriptor)
+		for name, d := range EachSymbol(b) {
+			declared[name] = d
+		}
+		for name, x := range EachSymbol(a) {
+			y, ok := declared[name]
+			if ok && !proto.Equal(declarationProto(x), declarationProto(y)) && !yield(x, y) {
+				return
+			}
+		}
+	}
+}
+
+// declarationProto returns the descriptor proto of a declaration without its nested declarations.
+func declarationProto(d protoreflect.Descriptor) proto.Message {
+	switch d := d.(type) {
+	case protoreflect.MessageDescriptor:
+		m := protodesc.ToDescriptorProto(d)
+		m.NestedType, m.EnumType, m.Extension = nil, nil, nil
+		return m
+	case protoreflect.FieldDescriptor:
+		return protodesc.ToFieldDescriptorProto(d)
+	case protoreflect.OneofDescriptor:
+		return protodesc.ToOneofDescriptorProto(d)
+	case protoreflect.EnumDescriptor:
+		return protodesc.ToEnumDescriptorProto(d)
+	case protoreflect.EnumValueDescriptor:
+		return protodesc.ToEnumValueDescriptorProto(d)
+	case protoreflect.ServiceDescriptor:
+		return protodesc.ToServiceDescriptorProto(d)
+	case protoreflect.MethodDescriptor:
+		return protodesc.ToMethodDescriptorProto(d)
+	}
+	return nil
+}
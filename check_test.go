@@ -0,0 +1,132 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const extProto = `
+	name: "ext.proto"
+	package: "test"
+	message_type { name: "Base" extension_range { start: 100 end: 200 } }
+	extension { name: "a" number: 100 label: LABEL_OPTIONAL type: TYPE_STRING extendee: ".test.Base" }
+	extension { name: "b" number: 101 label: LABEL_OPTIONAL type: TYPE_INT32 extendee: ".test.Base" }
+`
+
+// newExtensionTypes builds a type registry with dynamic types for the named extensions.
+func newExtensionTypes(files *protoregistry.Files, names ...protoreflect.FullName) *protoregistry.Types {
+	types := new(protoregistry.Types)
+	for _, name := range names {
+		xd := results.Must1(files.FindDescriptorByName(name)).(protoreflect.ExtensionDescriptor)
+		results.Must(types.RegisterExtension(dynamicpb.NewExtensionType(xd)))
+	}
+	return types
+}
+
+func ExampleEachOrphanExtension() {
+	types := newExtensionTypes(newFiles(extProto), "test.a")
+	for xt := range protoiter.EachOrphanExtension(types, newFiles(acmeProto)) {
+		fmt.Println(xt.TypeDescriptor().FullName())
+	}
+	// Output:
+	// test.a
+}
+
+func TestEachOrphanExtension(t *testing.T) {
+	files := newFiles(extProto)
+	types := newExtensionTypes(files, "test.a", "test.b")
+	for xt := range protoiter.EachOrphanExtension(types, files) {
+		t.Errorf("unexpected orphan %v", xt.TypeDescriptor().FullName())
+	}
+	var got []protoreflect.FullName
+	for xt := range protoiter.EachOrphanExtension(types, newFiles(acmeProto)) {
+		got = append(got, xt.TypeDescriptor().FullName())
+	}
+	slices.Sort(got)
+	if want := []protoreflect.FullName{"test.a", "test.b"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+}
+
+func TestEachPlaceholder(t *testing.T) {
+	fdp := new(descriptorpb.FileDescriptorProto)
+	results.Must(prototext.Unmarshal([]byte(`
+		name: "partial.proto"
+		package: "test"
+		dependency: "missing.proto"
+		message_type {
+			name: "M"
+			field { name: "a" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".other.Missing" }
+			field { name: "b" number: 2 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".other.Missing" }
+			field { name: "c" number: 3 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".other.Color" }
+		}
+		service {
+			name: "S"
+			method { name: "Get" input_type: ".test.M" output_type: ".other.Reply" }
+		}
+	`), fdp))
+	fd := results.Must1(protodesc.FileOptions{AllowUnresolvable: true}.New(fdp, new(protoregistry.Files)))
+	files := new(protoregistry.Files)
+	results.Must(files.RegisterFile(fd))
+
+	var got []string
+	for d := range protoiter.EachPlaceholder(files) {
+		if fd, ok := d.(protoreflect.FileDescriptor); ok {
+			got = append(got, fd.Path())
+		} else {
+			got = append(got, string(d.FullName()))
+		}
+	}
+	want := []string{"missing.proto", "other.Missing", "other.Color", "other.Reply"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	for range protoiter.EachPlaceholder(newFiles(acmeProto)) {
+		t.Error("a resolved registry must not have placeholders")
+	}
+}
+
+func ExampleEachNameConflict() {
+	plugin := newFiles(strings.NewReplacer(
+		`name: "data" number: 2 label: LABEL_OPTIONAL type: TYPE_BYTES`, `name: "data" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING`,
+		`name: "Get"`, `name: "Fetch"`,
+	).Replace(acmeProto))
+	for x, y := range protoiter.EachNameConflict(plugin, newFiles(acmeProto)) {
+		fmt.Println(x.FullName(), y.FullName())
+	}
+	// Output:
+	// acme.store.Blob acme.store.Blob
+	// acme.store.Blob.data acme.store.Blob.data
+	// acme.store.BlobService acme.store.BlobService
+}
+
+func TestEachNameConflict(t *testing.T) {
+	for x := range protoiter.EachNameConflict(newFiles(acmeProto), newFiles(acmeProto)) {
+		t.Errorf("identical registries must not conflict: %v", x.FullName())
+	}
+	// Declarations of different kinds conflict.
+	a := newFiles(`name: "a.proto" package: "test" message_type { name: "Thing" }`)
+	b := newFiles(`name: "b.proto" package: "test" enum_type { name: "Thing" value { name: "THING_UNSPECIFIED" number: 0 } }`)
+	n := 0
+	for x, y := range protoiter.EachNameConflict(a, b) {
+		n++
+		if _, ok := x.(protoreflect.MessageDescriptor); !ok || y.FullName() != "test.Thing" {
+			t.Errorf("unexpected conflict %v %v", x, y)
+		}
+	}
+	if n != 1 {
+		t.Errorf("got %d conflicts", n)
+	}
+}
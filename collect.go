@@ -0,0 +1,125 @@
+package protoiter
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SizedSeq is a sequence together with a hint of the number of values it yields.
+//
+// The collecting functions [Collect], [CollectMap] and [SortedBy] use the hint to preallocate exactly,
+// avoiding repeated growth when materializing large descriptor collections.
+// A negative Len means the length is unknown.
+type SizedSeq[T any] struct {
+	// Seq is the sequence of values.
+	Seq iter.Seq[T]
+
+	// Len is the number of values Seq is expected to yield, or negative if unknown.
+	Len int
+}
+
+// Sized pairs a sequence with a length hint.
+func Sized[T any](seq iter.Seq[T], n int) SizedSeq[T] {
+	return SizedSeq[T]{Seq: seq, Len: n}
+}
+
+// EachSized creates a sequential iterator over a collection of descriptors, sized by dd.Len().
+//
+// Unlike [Each], it yields the descriptors without their indices.
+//
+// Parameters:
+//   - dd: A collection of descriptors implementing the [Descriptors] interface
+//
+// Returns:
+//   - A sized sequence that yields each descriptor
+func EachSized[DD Descriptors[D], D protoreflect.Descriptor](dd DD) SizedSeq[D] {
+	seq := func(yield func(D) bool) {
+		for i := range dd.Len() {
+			if !yield(dd.Get(i)) {
+				return
+			}
+		}
+	}
+	return SizedSeq[D]{Seq: seq, Len: dd.Len()}
+}
+
+// Collect collects the values of s into a new slice preallocated from the length hint.
+func Collect[T any](s SizedSeq[T]) []T {
+	list := make([]T, 0, max(s.Len, 0))
+	for v := range s.Seq {
+		list = append(list, v)
+	}
+	return list
+}
+
+// CollectMap collects the values of s into a new map keyed by key(value), preallocated from the length hint.
+// A later value replaces an earlier one with the same key.
+func CollectMap[K comparable, T any](s SizedSeq[T], key func(T) K) map[K]T {
+	m := make(map[K]T, max(s.Len, 0))
+	for v := range s.Seq {
+		m[key(v)] = v
+	}
+	return m
+}
+
+// SortedBy collects the values of s and returns them stably sorted by key(value).
+func SortedBy[T any, K cmp.Ordered](s SizedSeq[T], key func(T) K) []T {
+	list := Collect(s)
+	slices.SortStableFunc(list, func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	})
+	return list
+}
+
+// CollectMessages drains a sequence of messages and errors, such as [EachDelimited] or [EachRow], into a new slice.
+//
+// The slice is preallocated with capHint. Collection stops at the first error, which is returned
+// together with the messages collected before it; the message yielded with the error is not included.
+//
+// Parameters:
+//   - seq: The sequence of messages, or errors
+//   - capHint: The expected number of messages
+//
+// Returns:
+//   - The collected messages, and the first error, if any
+func CollectMessages[M proto.Message](seq iter.Seq2[M, error], capHint int) ([]M, error) {
+	list := make([]M, 0, max(capHint, 0))
+	for m, err := range seq {
+		if err != nil {
+			return list, err
+		}
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+// Drain consumes a sequence of values and errors, such as [EachFileInSet] or [UnpackDeep], applying fn to each value.
+//
+// Unlike [CollectMessages], it does not stop at the first error: every value is passed to fn,
+// and the errors fn returns and the errors the sequence yields are gathered in order.
+// fn is not called for a value yielded together with an error.
+// The sequence itself decides whether to continue after yielding an error; most stop.
+//
+// Parameters:
+//   - seq: The sequence of values, or errors
+//   - fn: The function applied to each value
+//
+// Returns:
+//   - The errors joined with [errors.Join], or nil if there were none
+func Drain[T any](seq iter.Seq2[T, error], fn func(T) error) error {
+	var errs []error
+	for v, err := range seq {
+		if err == nil {
+			err = fn(v)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,112 @@
+package protoiter_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func ExampleSortedBy() {
+	fields := (&descriptorpb.FileOptions{}).ProtoReflect().Descriptor().Fields()
+	sorted := protoiter.SortedBy(protoiter.EachSized(fields), protoreflect.FieldDescriptor.Number)
+	for _, fd := range sorted[:3] {
+		fmt.Println(fd.Number(), fd.Name())
+	}
+	// Output:
+	// 1 java_package
+	// 8 java_outer_classname
+	// 9 optimize_for
+}
+
+func TestCollect(t *testing.T) {
+	fields := (&descriptorpb.FileOptions{}).ProtoReflect().Descriptor().Fields()
+	s := protoiter.EachSized(fields)
+	if s.Len != fields.Len() {
+		t.Errorf("Len = %d", s.Len)
+	}
+	list := protoiter.Collect(s)
+	if len(list) != fields.Len() || cap(list) != fields.Len() {
+		t.Errorf("len %d cap %d, want %d", len(list), cap(list), fields.Len())
+	}
+	byName := protoiter.CollectMap(s, protoreflect.FieldDescriptor.Name)
+	if byName["go_package"] != fields.ByName("go_package") {
+		t.Error("CollectMap must key by name")
+	}
+	unknown := protoiter.Sized(slices.Values([]int{3, 1, 2}), -1)
+	if got := protoiter.SortedBy(unknown, func(v int) int { return v }); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("SortedBy = %v", got)
+	}
+	for range s.Seq {
+		break
+	}
+}
+
+func ExampleCollectMessages() {
+	stream := delimited(durationpb.New(1), durationpb.New(2))
+	list, err := protoiter.CollectMessages(protoiter.EachDelimited(bytes.NewReader(stream), newDuration, protoiter.DelimitedOptions{}), 2)
+	fmt.Println(len(list), cap(list), err)
+	// Output:
+	// 2 2 <nil>
+}
+
+func TestCollectMessages(t *testing.T) {
+	stream := append(delimited(durationpb.New(1)), 1, 0xff)
+	stream = append(stream, delimited(durationpb.New(3))...)
+	list, err := protoiter.CollectMessages(protoiter.EachDelimited(bytes.NewReader(stream), newDuration, protoiter.DelimitedOptions{}), -1)
+	if len(list) != 1 || err == nil {
+		t.Errorf("collection must stop at the first error: %d messages, error %v", len(list), err)
+	}
+}
+
+func ExampleDrain() {
+	stream := delimited(durationpb.New(1), durationpb.New(2))
+	var total time.Duration
+	err := protoiter.Drain(protoiter.EachDelimited(bytes.NewReader(stream), newDuration, protoiter.DelimitedOptions{}), func(d *durationpb.Duration) error {
+		total += d.AsDuration()
+		return nil
+	})
+	fmt.Println(total, err)
+	// Output:
+	// 3ns <nil>
+}
+
+func TestDrain(t *testing.T) {
+	errOdd := errors.New("odd")
+	errSeq := errors.New("broken")
+	seq := func(yield func(int, error) bool) {
+		for i := range 4 {
+			if !yield(i, nil) {
+				return
+			}
+		}
+		yield(0, errSeq)
+	}
+	var got []int
+	err := protoiter.Drain(seq, func(v int) error {
+		got = append(got, v)
+		if v%2 == 1 {
+			return fmt.Errorf("%d: %w", v, errOdd)
+		}
+		return nil
+	})
+	if !slices.Equal(got, []int{0, 1, 2, 3}) {
+		t.Errorf("fn must be called for every value, got %v", got)
+	}
+	if !errors.Is(err, errOdd) || !errors.Is(err, errSeq) {
+		t.Errorf("the errors of fn and the sequence must be joined, got %v", err)
+	}
+	if err.Error() != "1: odd\n3: odd\nbroken" {
+		t.Errorf("the errors must be joined in order, got %q", err)
+	}
+	if err := protoiter.Drain(func(func(int, error) bool) {}, func(int) error { return nil }); err != nil {
+		t.Errorf("Drain = %v", err)
+	}
+}
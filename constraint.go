@@ -0,0 +1,56 @@
+package protoiter
+
+import (
+	"iter"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldConstraintName is the full name of the protovalidate field option extension.
+const fieldConstraintName protoreflect.FullName = "buf.validate.field"
+
+// EachConstraint creates a sequential iterator over the fields of a message descriptor
+// together with their protovalidate (buf.validate) constraints.
+//
+// The constraints are the value of the buf.validate.field option extension,
+// typically a buf.validate.FieldConstraints message, or nil if the field has no constraints.
+// The extension is recognized by its full name, so this package does not depend on the generated protovalidate package;
+// however the extension must have been resolvable when the options were parsed,
+// either because the generated package is linked into the binary or because the descriptors were built with a resolver that knows it.
+//
+// Parameters:
+//   - md: The message descriptor whose fields are iterated
+//
+// Returns:
+//   - An iterator sequence that yields each field descriptor and its constraints message, or nil
+func EachConstraint(md protoreflect.MessageDescriptor) iter.Seq2[protoreflect.FieldDescriptor, protoreflect.Message] {
+	return func(yield func(protoreflect.FieldDescriptor, protoreflect.Message) bool) {
+		fields := md.Fields()
+		for i := range fields.Len() {
+			fd := fields.Get(i)
+			var constraints protoreflect.Message
+			if v, ok := extensionByName(fd.Options(), fieldConstraintName); ok {
+				constraints = v.Message()
+			}
+			if !yield(fd, constraints) {
+				return
+			}
+		}
+	}
+}
+
+// extensionByName returns the value of the populated extension with the given full name in an options message.
+func extensionByName(options proto.Message, name protoreflect.FullName) (value protoreflect.Value, ok bool) {
+	if options == nil {
+		return value, false
+	}
+	options.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsExtension() && fd.FullName() == name {
+			value, ok = v, true
+			return false
+		}
+		return true
+	})
+	return value, ok
+}
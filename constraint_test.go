@@ -0,0 +1,64 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func ExampleEachConstraint() {
+	md := (*timestamppb.Timestamp)(nil).ProtoReflect().Descriptor()
+	for field, constraints := range protoiter.EachConstraint(md) {
+		fmt.Println(field.Name(), constraints != nil)
+	}
+	// Output:
+	// seconds false
+	// nanos false
+}
+
+const validateProto = `
+	name: "buf/validate/validate.proto"
+	package: "buf.validate"
+	dependency: "google/protobuf/descriptor.proto"
+	message_type {
+		name: "FieldConstraints"
+		field { name: "required" number: 25 label: LABEL_OPTIONAL type: TYPE_BOOL }
+	}
+	extension {
+		name: "field" number: 1159 label: LABEL_OPTIONAL type: TYPE_MESSAGE
+		type_name: ".buf.validate.FieldConstraints" extendee: ".google.protobuf.FieldOptions"
+	}
+`
+
+func TestEachConstraint(t *testing.T) {
+	files := newFiles(validateProto, `
+		name: "user.proto"
+		package: "test"
+		dependency: "buf/validate/validate.proto"
+		message_type {
+			name: "User"
+			field {
+				name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING
+				options { [buf.validate.field] { required: true } }
+			}
+			field { name: "nickname" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+		}
+	`)
+	md := results.Must1(files.FindDescriptorByName("test.User")).(protoreflect.MessageDescriptor)
+	got := make(map[protoreflect.Name]string)
+	for field, constraints := range protoiter.EachConstraint(md) {
+		if constraints == nil {
+			got[field.Name()] = "<nil>"
+			continue
+		}
+		required := constraints.Descriptor().Fields().ByName("required")
+		got[field.Name()] = fmt.Sprint(constraints.Get(required))
+	}
+	if got["name"] != "true" || got["nickname"] != "<nil>" || len(got) != 2 {
+		t.Errorf("unexpected constraints %v", got)
+	}
+}
@@ -0,0 +1,108 @@
+package protoiter
+
+import (
+	"iter"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachTypeCycle creates a sequential iterator over the groups of mutually recursive message types in a registry.
+//
+// A message references the message types of its fields, including list elements and map values.
+// Each strongly connected component of this reference graph with more than one member,
+// or with a single member that references itself, is yielded with its members sorted by full name.
+// Types outside files are followed too, so a cycle through an imported type is found.
+// Such types complicate storage layers that flatten messages into tables.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields each cycle of message types
+func EachTypeCycle(files Files) iter.Seq[[]protoreflect.MessageDescriptor] {
+	return func(yield func([]protoreflect.MessageDescriptor) bool) {
+		t := tarjan{index: make(map[protoreflect.FullName]int), low: make(map[protoreflect.FullName]int), onStack: make(map[protoreflect.FullName]bool)}
+		t.yield = yield
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			md, ok := d.(protoreflect.MessageDescriptor)
+			if !ok || md.IsMapEntry() {
+				return true
+			}
+			if _, visited := t.index[md.FullName()]; !visited {
+				return t.connect(md)
+			}
+			return true
+		})
+	}
+}
+
+// tarjan finds strongly connected components with Tarjan's algorithm.
+type tarjan struct {
+	next    int
+	index   map[protoreflect.FullName]int
+	low     map[protoreflect.FullName]int
+	onStack map[protoreflect.FullName]bool
+	stack   []protoreflect.MessageDescriptor
+	yield   func([]protoreflect.MessageDescriptor) bool
+}
+
+// connect visits md and reports false if yield returned false.
+func (t *tarjan) connect(md protoreflect.MessageDescriptor) bool {
+	name := md.FullName()
+	t.index[name], t.low[name] = t.next, t.next
+	t.next++
+	t.stack = append(t.stack, md)
+	t.onStack[name] = true
+	self := false
+	for _, ref := range referencedMessages(md) {
+		refName := ref.FullName()
+		if refName == name {
+			self = true
+		}
+		if _, visited := t.index[refName]; !visited {
+			if !t.connect(ref) {
+				return false
+			}
+			t.low[name] = min(t.low[name], t.low[refName])
+		} else if t.onStack[refName] {
+			t.low[name] = min(t.low[name], t.index[refName])
+		}
+	}
+	if t.low[name] != t.index[name] {
+		return true
+	}
+	i := len(t.stack) - 1
+	for t.stack[i].FullName() != name {
+		i--
+	}
+	component := slices.Clone(t.stack[i:])
+	t.stack = t.stack[:i]
+	for _, m := range component {
+		t.onStack[m.FullName()] = false
+	}
+	if len(component) == 1 && !self {
+		return true
+	}
+	slices.SortFunc(component, func(a, b protoreflect.MessageDescriptor) int {
+		return strings.Compare(string(a.FullName()), string(b.FullName()))
+	})
+	return t.yield(component)
+}
+
+// referencedMessages returns the message types of the fields of md, looking through map entries.
+func referencedMessages(md protoreflect.MessageDescriptor) []protoreflect.MessageDescriptor {
+	var refs []protoreflect.MessageDescriptor
+	fields := md.Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if fd.IsMap() {
+			fd = fd.MapValue()
+		}
+		if fd.Message() != nil {
+			refs = append(refs, fd.Message())
+		}
+	}
+	return refs
+}
@@ -0,0 +1,69 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const treeProto = `
+	name: "tree.proto"
+	package: "tree"
+	dependency: "google/protobuf/struct.proto"
+	message_type {
+		name: "Node"
+		field { name: "children" number: 1 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".tree.Node" }
+		field { name: "attrs" number: 2 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".tree.Node.AttrsEntry" }
+		nested_type {
+			name: "AttrsEntry"
+			field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+			field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".tree.Leaf" }
+			options { map_entry: true }
+		}
+	}
+	message_type {
+		name: "Leaf"
+		field { name: "owner" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".tree.Node" }
+		field { name: "extra" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Value" }
+	}
+	message_type { name: "Plain" field { name: "leaf" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".tree.Leaf" } }
+`
+
+func ExampleEachTypeCycle() {
+	var _ structpb.Value
+	for cycle := range protoiter.EachTypeCycle(newFiles(treeProto)) {
+		var names []protoreflect.FullName
+		for _, md := range cycle {
+			names = append(names, md.FullName())
+		}
+		fmt.Println(names)
+	}
+	// Output:
+	// [google.protobuf.ListValue google.protobuf.Struct google.protobuf.Value]
+	// [tree.Leaf tree.Node]
+}
+
+func TestEachTypeCycle(t *testing.T) {
+	files := newFiles(`
+		name: "self.proto"
+		package: "self"
+		message_type { name: "List" field { name: "next" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".self.List" } }
+		message_type { name: "Flat" }
+	`)
+	n := 0
+	for cycle := range protoiter.EachTypeCycle(files) {
+		n++
+		if len(cycle) != 1 || cycle[0].FullName() != "self.List" {
+			t.Errorf("unexpected cycle %v", cycle)
+		}
+	}
+	if n != 1 {
+		t.Errorf("found %d cycles, want 1", n)
+	}
+	for range protoiter.EachTypeCycle(newFiles(treeProto)) {
+		break
+	}
+}
@@ -0,0 +1,194 @@
+package protoiter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/goaux/protoiter/internal/reflectutil"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachDiffSummary creates a sequential iterator over concise, human-readable lines describing how b differs from a.
+//
+// Each line names a path and the change, for example:
+//
+//	amount: 3 -> 5
+//	status: PENDING -> DONE
+//	items[2].name: added
+//	labels["env"]: removed
+//	meta: added
+//
+// A field, list element or map entry present only in b is "added", one present only in a is "removed",
+// and scalars present in both are shown as the old and new values; nested messages are compared recursively.
+// A singular field without presence, such as a proto3 scalar, is always shown as the old and new values,
+// so setting it to its zero value is a change, not a removal.
+// Fields are visited in field-number order, list elements by index and map entries in key order,
+// so the output is stable and suitable for audit logs. Unknown fields are ignored.
+// If a and b are of different types, a single line describing the type change is yielded.
+//
+// Parameters:
+//   - a: The old message
+//   - b: The new message
+//
+// Returns:
+//   - An iterator sequence that yields one line per difference
+func EachDiffSummary(a, b proto.Message) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		ma, mb := a.ProtoReflect(), b.ProtoReflect()
+		if ma.Descriptor().FullName() != mb.Descriptor().FullName() {
+			yield(fmt.Sprintf("type: %s -> %s", ma.Descriptor().FullName(), mb.Descriptor().FullName()))
+			return
+		}
+		diffMessage("", ma, mb, yield)
+	}
+}
+
+func diffMessage(path string, a, b protoreflect.Message, yield func(string) bool) bool {
+	var fields []protoreflect.FieldDescriptor
+	seen := make(map[protoreflect.FieldNumber]bool)
+	collect := func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !seen[fd.Number()] {
+			seen[fd.Number()] = true
+			fields = append(fields, fd)
+		}
+		return true
+	}
+	a.Range(collect)
+	b.Range(collect)
+	slices.SortFunc(fields, func(x, y protoreflect.FieldDescriptor) int {
+		return int(x.Number()) - int(y.Number())
+	})
+	for _, fd := range fields {
+		p := joinPath(path, fieldStep(fd))
+		var ok bool
+		switch {
+		case fd.Cardinality() != protoreflect.Repeated && !fd.HasPresence():
+			// A field without presence is not set when it holds its zero value, so it changes rather than comes and goes.
+			ok = diffValue(p, fd, a.Get(fd), b.Get(fd), yield)
+		case !a.Has(fd):
+			ok = yield(p + ": added")
+		case !b.Has(fd):
+			ok = yield(p + ": removed")
+		case fd.IsMap():
+			ok = diffMap(p, fd.MapValue(), a.Get(fd).Map(), b.Get(fd).Map(), yield)
+		case fd.IsList():
+			ok = diffList(p, fd, a.Get(fd).List(), b.Get(fd).List(), yield)
+		default:
+			ok = diffValue(p, fd, a.Get(fd), b.Get(fd), yield)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func diffList(path string, fd protoreflect.FieldDescriptor, a, b protoreflect.List, yield func(string) bool) bool {
+	for i := range max(a.Len(), b.Len()) {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		var ok bool
+		switch {
+		case i >= a.Len():
+			ok = yield(p + ": added")
+		case i >= b.Len():
+			ok = yield(p + ": removed")
+		default:
+			ok = diffValue(p, fd, a.Get(i), b.Get(i), yield)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func diffMap(path string, fd protoreflect.FieldDescriptor, a, b protoreflect.Map, yield func(string) bool) bool {
+	var keys []protoreflect.MapKey
+	collect := func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	}
+	a.Range(collect)
+	b.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+		if !a.Has(k) {
+			keys = append(keys, k)
+		}
+		return true
+	})
+	slices.SortFunc(keys, reflectutil.CompareMapKeys)
+	for _, k := range keys {
+		p := fmt.Sprintf("%s[%s]", path, formatScalar(fd.ContainingMessage().Fields().ByNumber(1), k.Value()))
+		var ok bool
+		switch {
+		case !a.Has(k):
+			ok = yield(p + ": added")
+		case !b.Has(k):
+			ok = yield(p + ": removed")
+		default:
+			ok = diffValue(p, fd, a.Get(k), b.Get(k), yield)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// diffValue compares a singular value, list element or map value of the field fd.
+func diffValue(path string, fd protoreflect.FieldDescriptor, a, b protoreflect.Value, yield func(string) bool) bool {
+	if fd.Message() != nil {
+		return diffMessage(path, a.Message(), b.Message(), yield)
+	}
+	if scalarEqual(a, b) {
+		return true
+	}
+	return yield(fmt.Sprintf("%s: %s -> %s", path, formatScalar(fd, a), formatScalar(fd, b)))
+}
+
+func scalarEqual(a, b protoreflect.Value) bool {
+	switch x := a.Interface().(type) {
+	case []byte:
+		return bytes.Equal(x, b.Bytes())
+	case float32, float64:
+		y := b.Float()
+		return a.Float() == y || math.IsNaN(a.Float()) && math.IsNaN(y)
+	}
+	return a.Interface() == b.Interface()
+}
+
+// formatScalar renders a scalar value: strings quoted, bytes in base64 and enums by name.
+func formatScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch x := v.Interface().(type) {
+	case string:
+		return strconv.Quote(x)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x)
+	case protoreflect.EnumNumber:
+		if ev := fd.Enum().Values().ByNumber(x); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(x))
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// fieldStep returns the path step of a field: its text name, or its bracketed full name for an extension.
+func fieldStep(fd protoreflect.FieldDescriptor) string {
+	if fd.IsExtension() {
+		return "[" + string(fd.FullName()) + "]"
+	}
+	return fd.TextName()
+}
+
+func joinPath(path, step string) string {
+	if path == "" {
+		return step
+	}
+	return path + "." + step
+}
@@ -0,0 +1,83 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleEachDiffSummary() {
+	a := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String("amount"),
+		Number: proto.Int32(3),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	b := &descriptorpb.FieldDescriptorProto{
+		Name:    proto.String("amount"),
+		Number:  proto.Int32(5),
+		Label:   descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+		Options: &descriptorpb.FieldOptions{Deprecated: proto.Bool(true)},
+	}
+	for line := range protoiter.EachDiffSummary(a, b) {
+		fmt.Println(line)
+	}
+	// Output:
+	// number: 3 -> 5
+	// label: LABEL_OPTIONAL -> LABEL_REPEATED
+	// options: added
+}
+
+func TestEachDiffSummary(t *testing.T) {
+	a := &descriptorpb.DescriptorProto{Field: []*descriptorpb.FieldDescriptorProto{
+		{Name: proto.String("a")}, {Name: proto.String("b")}, {Name: proto.String("c")},
+	}}
+	b := &descriptorpb.DescriptorProto{Field: []*descriptorpb.FieldDescriptorProto{
+		{Name: proto.String("a")}, {Name: proto.String("x")}, {Number: proto.Int32(1)}, {},
+	}}
+	got := slices.Collect(protoiter.EachDiffSummary(a, b))
+	want := []string{
+		`field[1].name: "b" -> "x"`,
+		`field[2].name: removed`,
+		`field[2].number: added`,
+		`field[3]: added`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q", got)
+	}
+
+	sa := results.Must1(structpb.NewStruct(map[string]any{"env": "dev", "n": 1, "old": true}))
+	sb := results.Must1(structpb.NewStruct(map[string]any{"env": "prod", "n": 1, "new": nil}))
+	got = slices.Collect(protoiter.EachDiffSummary(sa, sb))
+	want = []string{
+		`fields["env"].string_value: "dev" -> "prod"`,
+		`fields["new"]: added`,
+		`fields["old"]: removed`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q", got)
+	}
+
+	if got := slices.Collect(protoiter.EachDiffSummary(sa, sa)); len(got) != 0 {
+		t.Errorf("equal messages differ: %q", got)
+	}
+	got = slices.Collect(protoiter.EachDiffSummary(sa, durationpb.New(1)))
+	if want := []string{"type: google.protobuf.Struct -> google.protobuf.Duration"}; !slices.Equal(got, want) {
+		t.Errorf("got %q", got)
+	}
+
+	// Proto3 scalars have no presence: a value changed to zero is not removed.
+	got = slices.Collect(protoiter.EachDiffSummary(&durationpb.Duration{Seconds: 5}, &durationpb.Duration{Nanos: 7}))
+	if want := []string{"seconds: 5 -> 0", "nanos: 0 -> 7"}; !slices.Equal(got, want) {
+		t.Errorf("got %q", got)
+	}
+	for range protoiter.EachDiffSummary(a, b) {
+		break
+	}
+}
@@ -0,0 +1,135 @@
+package protoiter
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/goaux/protoiter/internal/reflectutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DocEntry is the documentation of a declaration, as yielded by [EachDocEntry].
+type DocEntry struct {
+	// Kind is the kind of the declaration, using the names of [Query]: message, field, oneof, enum, enum_value,
+	// extension, service or method.
+	Kind string
+
+	// FullName is the full name of the declaration.
+	FullName protoreflect.FullName
+
+	// Signature is the declaration in proto syntax without its body and options,
+	// e.g. "repeated string tags = 3" or "rpc Get(GetRequest) returns (stream Blob)".
+	Signature string
+
+	// LeadingComment is the comment immediately preceding the declaration, if source info is available.
+	LeadingComment string
+
+	// Deprecated reports whether the declaration has the deprecated option set.
+	Deprecated bool
+
+	// Descriptor is the descriptor of the declaration.
+	Descriptor protoreflect.Descriptor
+}
+
+// EachDocEntry creates a sequential iterator over the documentation entries of the declarations in a file.
+//
+// Declarations are visited depth-first in declaration order, each before the declarations nested within it.
+// Synthetic map entry messages and the synthetic oneofs of proto3 optional fields are skipped.
+// Comments are only available if the file was built with source code info.
+//
+// Parameters:
+//   - fd: The file descriptor to document
+//
+// Returns:
+//   - An iterator sequence that yields the entry of each declaration
+func EachDocEntry(fd protoreflect.FileDescriptor) iter.Seq[DocEntry] {
+	return func(yield func(DocEntry) bool) {
+		eachDocEntry(fd, fd.SourceLocations(), yield)
+	}
+}
+
+func eachDocEntry(d protoreflect.Descriptor, locations protoreflect.SourceLocations, yield func(DocEntry) bool) bool {
+	return eachChild(d, func(d protoreflect.Descriptor) bool {
+		switch d := d.(type) {
+		case protoreflect.MessageDescriptor:
+			if d.IsMapEntry() {
+				return true
+			}
+		case protoreflect.OneofDescriptor:
+			if d.IsSynthetic() {
+				return true
+			}
+		}
+		entry := DocEntry{
+			Kind:           reflectutil.Kind(d),
+			FullName:       d.FullName(),
+			Signature:      signature(d),
+			LeadingComment: strings.TrimSpace(locations.ByDescriptor(d).LeadingComments),
+			Deprecated:     isDeprecated(d),
+			Descriptor:     d,
+		}
+		return yield(entry) && eachDocEntry(d, locations, yield)
+	})
+}
+
+// signature renders the declaration of d in proto syntax without its body and options.
+func signature(d protoreflect.Descriptor) string {
+	switch d := d.(type) {
+	case protoreflect.MessageDescriptor:
+		return "message " + string(d.Name())
+	case protoreflect.FieldDescriptor:
+		s := fmt.Sprintf("%s %s = %d", fieldType(d), d.Name(), d.Number())
+		switch {
+		case d.IsMap():
+		case d.IsList():
+			s = "repeated " + s
+		case d.Cardinality() == protoreflect.Required:
+			s = "required " + s
+		case d.HasOptionalKeyword():
+			s = "optional " + s
+		}
+		if d.IsExtension() {
+			s = fmt.Sprintf("extend %s { %s }", d.ContainingMessage().FullName(), s)
+		}
+		return s
+	case protoreflect.OneofDescriptor:
+		return "oneof " + string(d.Name())
+	case protoreflect.EnumDescriptor:
+		return "enum " + string(d.Name())
+	case protoreflect.EnumValueDescriptor:
+		return fmt.Sprintf("%s = %d", d.Name(), d.Number())
+	case protoreflect.ServiceDescriptor:
+		return "service " + string(d.Name())
+	case protoreflect.MethodDescriptor:
+		return fmt.Sprintf("rpc %s(%s%s) returns (%s%s)", d.Name(),
+			streamPrefix(d.IsStreamingClient()), d.Input().FullName(),
+			streamPrefix(d.IsStreamingServer()), d.Output().FullName())
+	}
+	return ""
+}
+
+// fieldType renders the type of a field, e.g. "int32", "acme.Blob" or "map<string, int32>".
+func fieldType(fd protoreflect.FieldDescriptor) string {
+	if fd.IsMap() {
+		return fmt.Sprintf("map<%s, %s>", fieldType(fd.MapKey()), fieldType(fd.MapValue()))
+	}
+	if name := fieldTypeName(fd); name != "" {
+		return string(name)
+	}
+	return fd.Kind().String()
+}
+
+func streamPrefix(stream bool) string {
+	if stream {
+		return "stream "
+	}
+	return ""
+}
+
+// isDeprecated reports whether the options of d set the deprecated field.
+func isDeprecated(d protoreflect.Descriptor) bool {
+	options := d.Options().ProtoReflect()
+	fd := options.Descriptor().Fields().ByName("deprecated")
+	return fd != nil && options.Get(fd).Bool()
+}
@@ -0,0 +1,70 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+)
+
+const docProto = `
+	name: "doc.proto"
+	package: "doc"
+	syntax: "proto3"
+	message_type {
+		name: "Blob"
+		field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "size" number: 2 label: LABEL_OPTIONAL type: TYPE_INT64 options { deprecated: true } }
+		field { name: "labels" number: 3 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".doc.Blob.LabelsEntry" }
+		field { name: "note" number: 4 label: LABEL_OPTIONAL type: TYPE_STRING proto3_optional: true oneof_index: 0 }
+		nested_type {
+			name: "LabelsEntry"
+			field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+			field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+			options { map_entry: true }
+		}
+		oneof_decl { name: "_note" }
+	}
+	enum_type { name: "Kind" value { name: "KIND_UNSPECIFIED" number: 0 } }
+	service {
+		name: "Store"
+		method { name: "Watch" input_type: ".doc.Blob" output_type: ".doc.Blob" server_streaming: true }
+	}
+	source_code_info {
+		location { path: [4, 0] span: [2, 0, 10, 1] leading_comments: " A stored object.\n" }
+		location { path: [4, 0, 2, 1] span: [4, 2, 20] leading_comments: " Size in bytes.\n" }
+	}
+`
+
+func ExampleEachDocEntry() {
+	fd := results.Must1(newFiles(docProto).FindFileByPath("doc.proto"))
+	for entry := range protoiter.EachDocEntry(fd) {
+		fmt.Printf("%-10s %-40s %q %v\n", entry.Kind, entry.Signature, entry.LeadingComment, entry.Deprecated)
+	}
+	// Output:
+	// message    message Blob                             "A stored object." false
+	// field      string id = 1                            "" false
+	// field      int64 size = 2                           "Size in bytes." true
+	// field      map<string, string> labels = 3           "" false
+	// field      optional string note = 4                 "" false
+	// enum       enum Kind                                "" false
+	// enum_value KIND_UNSPECIFIED = 0                     "" false
+	// service    service Store                            "" false
+	// method     rpc Watch(doc.Blob) returns (stream doc.Blob) "" false
+}
+
+func TestEachDocEntry(t *testing.T) {
+	fd := results.Must1(newFiles(extProto).FindFileByPath("ext.proto"))
+	var signatures []string
+	for entry := range protoiter.EachDocEntry(fd) {
+		signatures = append(signatures, entry.Signature)
+	}
+	want := "[message Base extend test.Base { optional string a = 100 } extend test.Base { optional int32 b = 101 }]"
+	if fmt.Sprint(signatures) != want {
+		t.Errorf("got %v", signatures)
+	}
+	for range protoiter.EachDocEntry(fd) {
+		break
+	}
+}
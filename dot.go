@@ -0,0 +1,78 @@
+package protoiter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DOTOptions configures [WriteDOT].
+//
+// The zero value writes every edge, with nodes labeled by file path or full name.
+type DOTOptions[D protoreflect.Descriptor] struct {
+	// Name is the name of the graph.
+	Name string
+
+	// Label returns the label of a node. If nil, the node ID is used.
+	Label func(D) string
+
+	// Filter reports whether an edge is written. If nil, every edge is written.
+	Filter func(from, to D) bool
+}
+
+// WriteDOT writes a sequence of edges between descriptors as a Graphviz DOT directed graph.
+//
+// Nodes are identified by file path for files and by full name otherwise, and are declared in order of first appearance.
+// Repeated edges are written once. Typical inputs are [EachImportEdge] for a file dependency graph,
+// or pairs of messages and the types of their fields for a type reference graph.
+//
+// Parameters:
+//   - w: The writer receiving the DOT source
+//   - edges: The sequence of edges, each from a descriptor to a descriptor it depends on
+//   - opts: The graph name, node labels and edge filter
+//
+// Returns:
+//   - The first write error, if any
+func WriteDOT[D protoreflect.Descriptor](w io.Writer, edges iter.Seq2[D, D], opts DOTOptions[D]) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", strconv.Quote(opts.Name))
+	nodes := make(map[string]bool)
+	type edge struct{ from, to string }
+	written := make(map[edge]bool)
+	node := func(d D) string {
+		id := strconv.Quote(nodeID(d))
+		if !nodes[id] {
+			nodes[id] = true
+			if opts.Label != nil {
+				fmt.Fprintf(bw, "\t%s [label=%s];\n", id, strconv.Quote(opts.Label(d)))
+			} else {
+				fmt.Fprintf(bw, "\t%s;\n", id)
+			}
+		}
+		return id
+	}
+	for from, to := range edges {
+		if opts.Filter != nil && !opts.Filter(from, to) {
+			continue
+		}
+		e := edge{node(from), node(to)}
+		if !written[e] {
+			written[e] = true
+			fmt.Fprintf(bw, "\t%s -> %s;\n", e.from, e.to)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// nodeID returns the path of a file or the full name of any other descriptor.
+func nodeID(d protoreflect.Descriptor) string {
+	if fd, ok := d.(protoreflect.FileDescriptor); ok {
+		return fd.Path()
+	}
+	return string(d.FullName())
+}
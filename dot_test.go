@@ -0,0 +1,74 @@
+package protoiter_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func ExampleWriteDOT() {
+	files := newFiles(envelopeProto, `name: "wrap.proto" dependency: "envelope.proto"`)
+	opts := protoiter.DOTOptions[protoreflect.FileDescriptor]{
+		Name: "imports",
+		Filter: func(from, to protoreflect.FileDescriptor) bool {
+			return from.Path() == "wrap.proto"
+		},
+	}
+	protoiter.WriteDOT(os.Stdout, protoiter.EachImportEdge(files), opts)
+	// Output:
+	// digraph "imports" {
+	// 	"wrap.proto";
+	// 	"envelope.proto";
+	// 	"wrap.proto" -> "envelope.proto";
+	// }
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestWriteDOT(t *testing.T) {
+	files := newFiles(acmeProto)
+	// A type reference graph from each message to the message and enum types of its fields.
+	// Every edge is yielded twice to check that it is written once.
+	refs := func(yield func(protoreflect.Descriptor, protoreflect.Descriptor) bool) {
+		for d := range protoiter.OfType[protoreflect.FieldDescriptor](protoiter.EachQuery(files, "field")) {
+			var to protoreflect.Descriptor = d.Enum()
+			if d.Message() != nil {
+				to = d.Message()
+			}
+			if d.Enum() == nil && d.Message() == nil {
+				continue
+			}
+			if !yield(d.ContainingMessage(), to) || !yield(d.ContainingMessage(), to) {
+				return
+			}
+		}
+	}
+	var sb strings.Builder
+	opts := protoiter.DOTOptions[protoreflect.Descriptor]{
+		Label: func(d protoreflect.Descriptor) string { return string(d.Name()) },
+	}
+	if err := protoiter.WriteDOT(&sb, refs, opts); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph "" {
+	"acme.store.Blob.Meta" [label="Meta"];
+	"acme.store.State" [label="State"];
+	"acme.store.Blob.Meta" -> "acme.store.State";
+}
+`
+	if sb.String() != want {
+		t.Errorf("got\n%s", sb.String())
+	}
+	if err := protoiter.WriteDOT(failingWriter{}, refs, opts); err == nil {
+		t.Error("a write error must be returned")
+	}
+}
@@ -174,3 +174,43 @@ func EachField(message protoreflect.Message) iter.Seq2[protoreflect.FieldDescrip
 		message.Range(yield)
 	}
 }
+
+// EachList creates a sequential iterator over the elements of a list value, such as a repeated field.
+//
+// The length is read before each element, so elements appended during iteration are visited as well.
+//
+// Parameters:
+//   - list: The list to iterate over
+//
+// Returns:
+//   - An iterator sequence that yields each index and its corresponding element
+func EachList(list protoreflect.List) iter.Seq2[int, protoreflect.Value] {
+	return func(yield func(int, protoreflect.Value) bool) {
+		for i := 0; i < list.Len(); i++ {
+			if !yield(i, list.Get(i)) {
+				return
+			}
+		}
+	}
+}
+
+// EachMap creates a sequential iterator over the entries of a map value, such as a map field.
+//
+// It returns an iterator of calling [protoreflect.Map.Range].
+//
+//	Range iterates over every map entry in an undefined order,
+//	calling f for each key and value encountered.
+//	Range calls f Len times unless f returns false, which stops iteration.
+//	While iterating, mutating operations may only be performed
+//	on the current map key.
+//
+// Parameters:
+//   - m: The map to iterate over
+//
+// Returns:
+//   - An iterator sequence that yields each map key and its corresponding value
+func EachMap(m protoreflect.Map) iter.Seq2[protoreflect.MapKey, protoreflect.Value] {
+	return func(yield func(protoreflect.MapKey, protoreflect.Value) bool) {
+		m.Range(yield)
+	}
+}
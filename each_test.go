@@ -13,6 +13,7 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -191,3 +192,57 @@ func TestEachField(t *testing.T) {
 		t.Errorf("must be equal\ngot\t%#v\nwant\t%#v", got, want)
 	}
 }
+
+func ExampleEachList() {
+	m := &descriptorpb.FileDescriptorProto{Dependency: []string{"a.proto", "b.proto"}}
+	list := m.ProtoReflect().Get(m.ProtoReflect().Descriptor().Fields().ByName("dependency")).List()
+	for i, v := range protoiter.EachList(list) {
+		fmt.Println(i, v)
+	}
+	// Output:
+	// 0 a.proto
+	// 1 b.proto
+}
+
+func TestEachList(t *testing.T) {
+	m := &descriptorpb.FileDescriptorProto{Dependency: []string{"a.proto"}}
+	list := m.ProtoReflect().Mutable(m.ProtoReflect().Descriptor().Fields().ByName("dependency")).List()
+	var got []string
+	for i, v := range protoiter.EachList(list) {
+		got = append(got, v.String())
+		if i == 0 {
+			list.Append(protoreflect.ValueOfString("b.proto"))
+		}
+	}
+	if want := []string{"a.proto", "b.proto"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	for range protoiter.EachList(list) {
+		break
+	}
+}
+
+func ExampleEachMap() {
+	s := results.Must1(structpb.NewStruct(map[string]any{"a": 1, "b": "x"}))
+	fields := s.ProtoReflect().Get(s.ProtoReflect().Descriptor().Fields().ByName("fields")).Map()
+	for k, v := range protoiter.EachMap(fields) {
+		fmt.Println(k, v.Message().Interface().(*structpb.Value).AsInterface())
+	}
+	// Unordered output:
+	// a 1
+	// b x
+}
+
+func TestEachMap(t *testing.T) {
+	s := results.Must1(structpb.NewStruct(map[string]any{"a": 1, "b": 2, "c": 3}))
+	fields := s.ProtoReflect().Get(s.ProtoReflect().Descriptor().Fields().ByName("fields")).Map()
+	n := 0
+	for range protoiter.EachMap(fields) {
+		if n++; n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("iteration must stop after break, got %d", n)
+	}
+}
@@ -0,0 +1,70 @@
+package protoiter
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachEnumNumber creates a sequential iterator over the values of an enum descriptor sorted by number.
+//
+// Unlike [Each] over [protoreflect.EnumDescriptor.Values], which follows declaration order,
+// the values are ordered by number, as exhaustive switch generators and validators need them.
+// Aliases, allowed by the allow_alias option, share a number and are yielded in declaration order.
+//
+// Parameters:
+//   - ed: The enum descriptor whose values are iterated
+//
+// Returns:
+//   - An iterator sequence that yields the number and name of each value
+func EachEnumNumber(ed protoreflect.EnumDescriptor) iter.Seq2[protoreflect.EnumNumber, protoreflect.Name] {
+	return func(yield func(protoreflect.EnumNumber, protoreflect.Name) bool) {
+		for _, vd := range sortedEnumValues(ed) {
+			if !yield(vd.Number(), vd.Name()) {
+				return
+			}
+		}
+	}
+}
+
+// EachEnumGap creates a sequential iterator over the gaps between the numbers of a closed enum.
+//
+// A closed enum, such as a proto2 enum, only accepts its declared numbers: any other number is treated
+// as an unknown field when parsed, so validators and generators may need to reject the gaps explicitly.
+// Each gap is a range of undeclared numbers between the smallest and the largest declared number,
+// yielded by its first and last numbers, inclusive, in ascending order. Reserved numbers are part of the gaps.
+// An open enum accepts every number and has no gaps, so nothing is yielded for it.
+//
+// Parameters:
+//   - ed: The enum descriptor whose gaps are iterated
+//
+// Returns:
+//   - An iterator sequence that yields the first and last numbers of each gap
+func EachEnumGap(ed protoreflect.EnumDescriptor) iter.Seq2[protoreflect.EnumNumber, protoreflect.EnumNumber] {
+	return func(yield func(protoreflect.EnumNumber, protoreflect.EnumNumber) bool) {
+		if !ed.IsClosed() {
+			return
+		}
+		values := sortedEnumValues(ed)
+		for i := 1; i < len(values); i++ {
+			prev, next := values[i-1].Number(), values[i].Number()
+			if int64(next)-int64(prev) > 1 && !yield(prev+1, next-1) {
+				return
+			}
+		}
+	}
+}
+
+// sortedEnumValues returns the values of ed sorted by number, keeping aliases in declaration order.
+func sortedEnumValues(ed protoreflect.EnumDescriptor) []protoreflect.EnumValueDescriptor {
+	values := make([]protoreflect.EnumValueDescriptor, 0, ed.Values().Len())
+	for _, vd := range Each(ed.Values()) {
+		values = append(values, vd)
+	}
+	slices.SortStableFunc(values, func(x, y protoreflect.EnumValueDescriptor) int {
+		return cmp.Compare(x.Number(), y.Number())
+	})
+	return values
+}
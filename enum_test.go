@@ -0,0 +1,121 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func ExampleEachEnumNumber() {
+	ed := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Descriptor()
+	for number, name := range protoiter.EachEnumNumber(ed) {
+		fmt.Println(number, name)
+	}
+	// Output:
+	// 1 LABEL_OPTIONAL
+	// 2 LABEL_REQUIRED
+	// 3 LABEL_REPEATED
+}
+
+func ExampleEachEnumGap() {
+	files := newFiles(`
+		name: "status.proto"
+		package: "test"
+		enum_type {
+			name: "Status"
+			value { name: "ACTIVE" number: 1 }
+			value { name: "ARCHIVED" number: 9 }
+			value { name: "DELETED" number: 4 }
+		}
+	`)
+	ed := results.Must1(files.FindDescriptorByName("test.Status")).(protoreflect.EnumDescriptor)
+	for first, last := range protoiter.EachEnumGap(ed) {
+		fmt.Println(first, last)
+	}
+	// Output:
+	// 2 3
+	// 5 8
+}
+
+func TestEachEnumNumber(t *testing.T) {
+	files := newFiles(`
+		name: "alias.proto"
+		package: "test"
+		enum_type {
+			name: "E"
+			value { name: "C" number: 3 }
+			value { name: "A" number: -1 }
+			value { name: "B" number: 3 }
+			value { name: "D" number: 0 }
+			options { allow_alias: true }
+		}
+	`)
+	ed := results.Must1(files.FindDescriptorByName("test.E")).(protoreflect.EnumDescriptor)
+	var got []string
+	for number, name := range protoiter.EachEnumNumber(ed) {
+		got = append(got, fmt.Sprint(number, name))
+	}
+	if want := []string{"-1A", "0D", "3C", "3B"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	for range protoiter.EachEnumNumber(ed) {
+		break
+	}
+
+	var gaps []string
+	for first, last := range protoiter.EachEnumGap(ed) {
+		gaps = append(gaps, fmt.Sprint(first, "..", last))
+	}
+	if want := []string{"1..2"}; !slices.Equal(gaps, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", gaps, want)
+	}
+}
+
+func TestEachEnumGap(t *testing.T) {
+	files := newFiles(`
+		name: "open.proto"
+		package: "test"
+		syntax: "proto3"
+		enum_type {
+			name: "Open"
+			value { name: "UNSPECIFIED" number: 0 }
+			value { name: "FAR" number: 100 }
+		}
+	`)
+	ed := results.Must1(files.FindDescriptorByName("test.Open")).(protoreflect.EnumDescriptor)
+	for first, last := range protoiter.EachEnumGap(ed) {
+		t.Errorf("an open enum has no gaps, got %d..%d", first, last)
+	}
+
+	closed := descriptorpb.FieldDescriptorProto_TYPE_INT32.Descriptor()
+	for first, last := range protoiter.EachEnumGap(closed) {
+		t.Errorf("FieldDescriptorProto.Type has no gaps, got %d..%d", first, last)
+	}
+	closed = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Descriptor()
+	for first, last := range protoiter.EachEnumGap(closed) {
+		t.Errorf("FieldDescriptorProto.Label has no gaps, got %d..%d", first, last)
+	}
+
+	files = newFiles(`
+		name: "wide.proto"
+		package: "test"
+		enum_type {
+			name: "Wide"
+			value { name: "LOW" number: -2 }
+			value { name: "HIGH" number: 2147483647 }
+		}
+	`)
+	wide := results.Must1(files.FindDescriptorByName("test.Wide")).(protoreflect.EnumDescriptor)
+	var gaps []string
+	for first, last := range protoiter.EachEnumGap(wide) {
+		gaps = append(gaps, fmt.Sprint(first, "..", last))
+	}
+	if want := []string{"-1..2147483646"}; !slices.Equal(gaps, want) {
+		t.Errorf("the difference must not overflow\ngot\t%v\nwant\t%v", gaps, want)
+	}
+}
@@ -0,0 +1,90 @@
+package protoiter
+
+import (
+	"iter"
+	"strconv"
+
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/reflect/protorange"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Event is the kind of a traversal event, marking where a node of a tree opens or closes.
+type Event int
+
+const (
+	// Enter is yielded before the children of a node.
+	Enter Event = iota + 1
+
+	// Leave is yielded after the children of a node.
+	Leave
+)
+
+// String returns "enter" or "leave".
+func (e Event) String() string {
+	switch e {
+	case Enter:
+		return "enter"
+	case Leave:
+		return "leave"
+	}
+	return "event(" + strconv.Itoa(int(e)) + ")"
+}
+
+// EachEvent creates a sequential iterator over the push and pop structure of a message value tree,
+// as traversed by [protorange].
+//
+// Every value, starting with the message itself, is yielded once with [Enter] and once with [Leave],
+// and the events are properly nested, so consumers can maintain open/close structure such as indentation or brackets.
+// Fields are visited in ascending field-number order and map entries in key order; google.protobuf.Any messages are not expanded.
+// The yielded [protopath.Values] is only valid until the next iteration; use slices.Clone on its fields to retain it.
+//
+// Parameters:
+//   - message: The protocol buffer message to traverse
+//
+// Returns:
+//   - An iterator sequence that yields each event and the path and values leading to the current node
+func EachEvent(message protoreflect.Message) iter.Seq2[Event, protopath.Values] {
+	return func(yield func(Event, protopath.Values) bool) {
+		done := false
+		emit := func(e Event) func(protopath.Values) error {
+			return func(p protopath.Values) error {
+				if done {
+					return nil
+				}
+				if !yield(e, p) {
+					done = true
+					return protorange.Terminate
+				}
+				return nil
+			}
+		}
+		noAnyExpansion.Range(message, emit(Enter), emit(Leave))
+	}
+}
+
+// EachDescriptorEvent creates a sequential iterator over the open/close structure of a descriptor hierarchy.
+//
+// The root and every descriptor declared within it, such as messages, fields, enums and their values,
+// services and their methods, are yielded once with [Enter] before their children and once with [Leave] after them,
+// in declaration order. Leaf descriptors like fields are entered and left immediately.
+// This lets generators open and close sections without maintaining their own parent stack.
+//
+// Parameters:
+//   - root: The descriptor to traverse, typically a file, message or service
+//
+// Returns:
+//   - An iterator sequence that yields each event and the descriptor it applies to
+func EachDescriptorEvent(root protoreflect.Descriptor) iter.Seq2[Event, protoreflect.Descriptor] {
+	return func(yield func(Event, protoreflect.Descriptor) bool) {
+		descriptorEvents(root, yield)
+	}
+}
+
+func descriptorEvents(d protoreflect.Descriptor, yield func(Event, protoreflect.Descriptor) bool) bool {
+	return yield(Enter, d) &&
+		eachChild(d, func(child protoreflect.Descriptor) bool {
+			return descriptorEvents(child, yield)
+		}) &&
+		yield(Leave, d)
+}
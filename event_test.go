@@ -0,0 +1,122 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func ExampleEachEvent() {
+	m := &descriptorpb.DescriptorProto{
+		Name:  proto.String("M"),
+		Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("id")}},
+	}
+	depth := 0
+	for event, values := range protoiter.EachEvent(m.ProtoReflect()) {
+		if event == protoiter.Leave {
+			depth--
+			continue
+		}
+		fmt.Printf("%s%s\n", strings.Repeat("  ", depth), values.Index(-1).Step)
+		depth++
+	}
+	// Output:
+	// (google.protobuf.DescriptorProto)
+	//   .name
+	//   .field
+	//     [0]
+	//       .name
+}
+
+func TestEachEvent(t *testing.T) {
+	m := &descriptorpb.DescriptorProto{
+		Name:  proto.String("M"),
+		Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("a")}, {Name: proto.String("b")}},
+	}
+	var stack []string
+	n := 0
+	for event, values := range protoiter.EachEvent(m.ProtoReflect()) {
+		n++
+		path := values.Path.String()
+		switch event {
+		case protoiter.Enter:
+			stack = append(stack, path)
+		case protoiter.Leave:
+			if top := stack[len(stack)-1]; top != path {
+				t.Fatalf("leave %s must match enter %s", path, top)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			t.Fatalf("unexpected event %v", event)
+		}
+	}
+	if len(stack) != 0 || n != 2*7 {
+		t.Errorf("events must be balanced, got %d events and %v open", n, stack)
+	}
+
+	n = 0
+	for range protoiter.EachEvent(m.ProtoReflect()) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	if n != 3 {
+		t.Errorf("must stop after break, got %d", n)
+	}
+	if protoiter.Enter.String() != "enter" || protoiter.Leave.String() != "leave" || protoiter.Event(0).String() != "event(0)" {
+		t.Error("unexpected event names")
+	}
+}
+
+func ExampleEachDescriptorEvent() {
+	files := newFiles(acmeProto)
+	file := results.Must1(files.FindFileByPath("acme/store.proto"))
+	for event, d := range protoiter.EachDescriptorEvent(file) {
+		switch d.(type) {
+		case protoreflect.MessageDescriptor, protoreflect.ServiceDescriptor:
+			fmt.Println(event, d.Name())
+		}
+	}
+	// Output:
+	// enter Blob
+	// enter Meta
+	// leave Meta
+	// leave Blob
+	// enter BlobService
+	// leave BlobService
+}
+
+func TestEachDescriptorEvent(t *testing.T) {
+	files := newFiles(acmeProto)
+	file := results.Must1(files.FindFileByPath("acme/store.proto"))
+	var stack []protoreflect.Descriptor
+	n := 0
+	for event, d := range protoiter.EachDescriptorEvent(file) {
+		n++
+		if event == protoiter.Enter {
+			if len(stack) > 0 && d.Parent() != stack[len(stack)-1] {
+				t.Errorf("%v must be entered inside its parent", d.FullName())
+			}
+			stack = append(stack, d)
+			continue
+		}
+		if stack[len(stack)-1] != d {
+			t.Fatalf("leave %v must match the last enter", d.FullName())
+		}
+		stack = stack[:len(stack)-1]
+	}
+	// file, 2 messages, 5 fields, 1 enum, 2 values, 1 service, 1 method
+	if len(stack) != 0 || n != 2*13 {
+		t.Errorf("events must be balanced, got %d events", n)
+	}
+	for range protoiter.EachDescriptorEvent(file) {
+		break
+	}
+}
@@ -0,0 +1,282 @@
+package protoiter
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// EachFieldWithExtensions creates a sequential iterator over the populated fields of a message,
+// including extensions that are only present as unknown fields.
+//
+// It first yields the populated fields like [EachField]. It then reparses the unknown fields of the message
+// against the extensions of its type found in types, and yields each recognized extension with its typed value.
+// This recovers extensions from a message that was unmarshaled without a resolver that knew them.
+// Each unknown field is parsed on its own: a field that cannot be parsed as its extension is left out
+// without affecting the others, and parsing stops at the first field whose wire format is malformed.
+// The message itself is not modified.
+//
+// Parameters:
+//   - message: The protocol buffer message to iterate over
+//   - types: A Types implementation providing the known extension types
+//
+// Returns:
+//   - An iterator sequence that yields each field descriptor and its corresponding value
+func EachFieldWithExtensions(message protoreflect.Message, types Types) iter.Seq2[protoreflect.FieldDescriptor, protoreflect.Value] {
+	return func(yield func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+		ok := true
+		message.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			ok = yield(fd, v)
+			return ok
+		})
+		unknown := message.GetUnknown()
+		if !ok || len(unknown) == 0 {
+			return
+		}
+		resolver := extensionsOf(types, message.Descriptor().FullName())
+		if len(resolver) == 0 {
+			return
+		}
+		reparsed := message.New()
+		opts := proto.UnmarshalOptions{Resolver: resolver, AllowPartial: true}
+		for len(unknown) > 0 {
+			num, _, n := protowire.ConsumeField(unknown)
+			if n < 0 {
+				break
+			}
+			field := unknown[:n]
+			unknown = unknown[n:]
+			if _, ok := resolver[num]; !ok {
+				continue
+			}
+			// Each field is parsed on its own, so that one malformed field does not hide the others.
+			m := message.New()
+			if err := opts.Unmarshal(field, m.Interface()); err != nil {
+				continue
+			}
+			proto.Merge(reparsed.Interface(), m.Interface())
+		}
+		reparsed.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			if !fd.IsExtension() {
+				return true
+			}
+			return yield(fd, v)
+		})
+	}
+}
+
+// EachPopulatedExtension creates a sequential iterator over the extension fields populated on a message.
+//
+// The extensions are those visited by [protoreflect.Message.Range], in its undefined order; regular fields are skipped.
+// Each is yielded with its extension type: by default the type the value was set or unmarshaled with,
+// or, if types is not nil, the type registered in types for the same message and number,
+// such as the generated type of an extension the message only knows dynamically.
+// The value is then converted to the registered type through the wire format, so that
+// [protoreflect.ExtensionType.InterfaceOf] accepts it; an extension whose value cannot be converted,
+// or that is not registered in types, is yielded with its own type and value.
+// Extensions that are only present as unknown fields are not visited; see [EachFieldWithExtensions].
+//
+// Parameters:
+//   - message: The protocol buffer message to iterate over
+//   - types: A Types implementation to resolve the extension types, or nil
+//
+// Returns:
+//   - An iterator sequence that yields each populated extension type and its corresponding value
+func EachPopulatedExtension(message protoreflect.Message, types Types) iter.Seq2[protoreflect.ExtensionType, protoreflect.Value] {
+	return func(yield func(protoreflect.ExtensionType, protoreflect.Value) bool) {
+		var resolver extensionResolver
+		if types != nil {
+			resolver = extensionsOf(types, message.Descriptor().FullName())
+		}
+		message.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			xd, ok := fd.(protoreflect.ExtensionTypeDescriptor)
+			if !ok || !fd.IsExtension() {
+				return true
+			}
+			xt := xd.Type()
+			if registered, ok := resolver[fd.Number()]; ok && registered != xt {
+				if converted, ok := convertExtension(message, fd, v, registered); ok {
+					xt, v = registered, converted
+				}
+			}
+			return yield(xt, v)
+		})
+	}
+}
+
+// convertExtension converts the value v of the extension fd of message to the representation of xt
+// by encoding it and decoding it as xt, reporting false if that fails.
+func convertExtension(message protoreflect.Message, fd protoreflect.FieldDescriptor, v protoreflect.Value, xt protoreflect.ExtensionType) (protoreflect.Value, bool) {
+	single := message.New()
+	single.Set(fd, v)
+	b, err := proto.MarshalOptions{AllowPartial: true}.Marshal(single.Interface())
+	if err != nil {
+		return protoreflect.Value{}, false
+	}
+	decoded := message.New()
+	opts := proto.UnmarshalOptions{Resolver: extensionResolver{fd.Number(): xt}, AllowPartial: true}
+	if err := opts.Unmarshal(b, decoded.Interface()); err != nil || !decoded.Has(xt.TypeDescriptor()) {
+		return protoreflect.Value{}, false
+	}
+	return decoded.Get(xt.TypeDescriptor()), true
+}
+
+// extensionResolver resolves the extensions of a single message by number.
+type extensionResolver map[protoreflect.FieldNumber]protoreflect.ExtensionType
+
+var _ protoregistry.ExtensionTypeResolver = extensionResolver(nil)
+
+// extensionsOf collects the extensions of the named message registered in types.
+func extensionsOf(types Types, message protoreflect.FullName) extensionResolver {
+	r := make(extensionResolver)
+	types.RangeExtensionsByMessage(message, func(xt protoreflect.ExtensionType) bool {
+		r[xt.TypeDescriptor().Number()] = xt
+		return true
+	})
+	return r
+}
+
+func (r extensionResolver) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	for _, xt := range r {
+		if xt.TypeDescriptor().FullName() == field {
+			return xt, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+func (r extensionResolver) FindExtensionByNumber(_ protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	if xt, ok := r[field]; ok {
+		return xt, nil
+	}
+	return nil, protoregistry.NotFound
+}
+
+// EachExtensionByMessageSorted creates a sequential iterator over the extension types for a specific message
+// in ascending field-number order.
+//
+// Unlike [EachExtensionByMessage], whose order is undefined, the extensions are materialized and sorted first,
+// so output derived from them, such as generated documentation or wire dumps, is reproducible.
+//
+// Parameters:
+//   - types: A Types implementation providing access to extension types
+//   - message: The full name of the message to filter extension types
+//
+// Returns:
+//   - An iterator sequence that yields extension types for the specified message
+func EachExtensionByMessageSorted(types Types, message protoreflect.FullName) iter.Seq[protoreflect.ExtensionType] {
+	return func(yield func(protoreflect.ExtensionType) bool) {
+		for _, xt := range sortedExtensions(types, message) {
+			if !yield(xt) {
+				return
+			}
+		}
+	}
+}
+
+// EachExtensionNumber creates a sequential iterator over the extension types for a specific message
+// keyed by field number, in ascending order.
+//
+// Parameters:
+//   - types: A Types implementation providing access to extension types
+//   - message: The full name of the message to filter extension types
+//
+// Returns:
+//   - An iterator sequence that yields the field number and type of each extension
+func EachExtensionNumber(types Types, message protoreflect.FullName) iter.Seq2[protoreflect.FieldNumber, protoreflect.ExtensionType] {
+	return func(yield func(protoreflect.FieldNumber, protoreflect.ExtensionType) bool) {
+		for _, xt := range sortedExtensions(types, message) {
+			if !yield(xt.TypeDescriptor().Number(), xt) {
+				return
+			}
+		}
+	}
+}
+
+// EachOutOfRangeExtension creates a sequential iterator over the registered extensions of a message
+// whose field numbers fall outside the extension ranges the message declares, in ascending order.
+//
+// Such registrations come from extensions compiled against a different version of the message
+// and cannot be set on it.
+//
+// Parameters:
+//   - types: A Types implementation providing access to extension types
+//   - md: The descriptor of the extended message
+//
+// Returns:
+//   - An iterator sequence that yields the field number and type of each out-of-range extension
+func EachOutOfRangeExtension(types Types, md protoreflect.MessageDescriptor) iter.Seq2[protoreflect.FieldNumber, protoreflect.ExtensionType] {
+	return func(yield func(protoreflect.FieldNumber, protoreflect.ExtensionType) bool) {
+		ranges := md.ExtensionRanges()
+		for n, xt := range EachExtensionNumber(types, md.FullName()) {
+			if !ranges.Has(n) && !yield(n, xt) {
+				return
+			}
+		}
+	}
+}
+
+// sortedExtensions returns the extensions of message in types sorted by field number.
+func sortedExtensions(types Types, message protoreflect.FullName) []protoreflect.ExtensionType {
+	var list []protoreflect.ExtensionType
+	types.RangeExtensionsByMessage(message, func(xt protoreflect.ExtensionType) bool {
+		list = append(list, xt)
+		return true
+	})
+	slices.SortFunc(list, func(a, b protoreflect.ExtensionType) int {
+		return cmp.Compare(a.TypeDescriptor().Number(), b.TypeDescriptor().Number())
+	})
+	return list
+}
+
+// EachExtensionConflict creates a sequential iterator over the pairs of extensions in a registry
+// that extend the same message with the same field number.
+//
+// A [protoregistry.Types] refuses such registrations, but registries merged from several descriptor sources
+// may contain them, and the conflict otherwise surfaces only when a message is unmarshaled.
+// Extensions with the same full name are the same extension registered twice and do not conflict.
+// Note that [TypesChain] hides the extensions it shadows; to check registries before chaining them,
+// pass a Types that ranges over the extensions of all of them.
+// The pairs are yielded by extendee name, then by field number, with the types within a pair ordered by full name.
+//
+// Parameters:
+//   - types: A Types implementation providing access to extension types
+//
+// Returns:
+//   - An iterator sequence that yields each pair of conflicting extension types
+func EachExtensionConflict(types Types) iter.Seq2[protoreflect.ExtensionType, protoreflect.ExtensionType] {
+	return func(yield func(protoreflect.ExtensionType, protoreflect.ExtensionType) bool) {
+		type key struct {
+			extendee protoreflect.FullName
+			number   protoreflect.FieldNumber
+		}
+		groups := make(map[key][]protoreflect.ExtensionType)
+		for xt := range EachExtension(types) {
+			xd := xt.TypeDescriptor()
+			k := key{xd.ContainingMessage().FullName(), xd.Number()}
+			groups[k] = append(groups[k], xt)
+		}
+		keys := slices.SortedFunc(maps.Keys(groups), func(a, b key) int {
+			return cmp.Or(cmp.Compare(a.extendee, b.extendee), cmp.Compare(a.number, b.number))
+		})
+		for _, k := range keys {
+			group := groups[k]
+			slices.SortFunc(group, func(a, b protoreflect.ExtensionType) int {
+				return cmp.Compare(a.TypeDescriptor().FullName(), b.TypeDescriptor().FullName())
+			})
+			for i, x := range group {
+				for _, y := range group[i+1:] {
+					if x.TypeDescriptor().FullName() != y.TypeDescriptor().FullName() && !yield(x, y) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
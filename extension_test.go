@@ -0,0 +1,260 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/gofeaturespb"
+)
+
+// newBase returns a dynamic test.Base holding extensions a and b as unknown fields.
+func newBase(files *protoregistry.Files) protoreflect.Message {
+	md := results.Must1(files.FindDescriptorByName("test.Base")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	var b []byte
+	b = protowire.AppendTag(b, 100, protowire.BytesType)
+	b = protowire.AppendString(b, "hello")
+	b = protowire.AppendTag(b, 101, protowire.VarintType)
+	b = protowire.AppendVarint(b, 7)
+	m.SetUnknown(b)
+	return m
+}
+
+func ExampleEachFieldWithExtensions() {
+	files := newFiles(extProto)
+	m := newBase(files)
+	for fd, v := range protoiter.EachFieldWithExtensions(m, newExtensionTypes(files, "test.a")) {
+		fmt.Println(fd.FullName(), v)
+	}
+	// Output:
+	// test.a hello
+}
+
+func TestEachFieldWithExtensions(t *testing.T) {
+	files := newFiles(extProto)
+	m := newBase(files)
+	got := make(map[protoreflect.FullName]any)
+	for fd, v := range protoiter.EachFieldWithExtensions(m, newExtensionTypes(files, "test.a", "test.b")) {
+		got[fd.FullName()] = v.Interface()
+	}
+	if fmt.Sprint(got) != "map[test.a:hello test.b:7]" {
+		t.Errorf("unexpected fields %v", got)
+	}
+	if len(m.GetUnknown()) == 0 {
+		t.Error("the message must not be modified")
+	}
+	for range protoiter.EachFieldWithExtensions(m, new(protoregistry.Types)) {
+		t.Error("no extension is known")
+	}
+	for range protoiter.EachFieldWithExtensions(m, newExtensionTypes(files, "test.a", "test.b")) {
+		break
+	}
+
+	// A malformed extension between two good ones, followed by a truncated field.
+	files = newFiles(extProto, `
+		name: "nested.proto"
+		package: "test"
+		dependency: "ext.proto"
+		extension { name: "c" number: 102 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.Base" extendee: ".test.Base" }
+	`)
+	m = newBase(files)
+	b := m.GetUnknown()
+	b = protowire.AppendTag(b, 102, protowire.BytesType)
+	b = protowire.AppendBytes(b, []byte{0xff})
+	b = protowire.AppendTag(b, 100, protowire.BytesType)
+	b = protowire.AppendString(b, "again")
+	b = protowire.AppendTag(b, 101, protowire.BytesType)
+	m.SetUnknown(b)
+	got = make(map[protoreflect.FullName]any)
+	for fd, v := range protoiter.EachFieldWithExtensions(m, newExtensionTypes(files, "test.a", "test.b", "test.c")) {
+		got[fd.FullName()] = v.Interface()
+	}
+	if fmt.Sprint(got) != "map[test.a:again test.b:7]" {
+		t.Errorf("the good extensions must be kept, got %v", got)
+	}
+}
+
+func ExampleEachPopulatedExtension() {
+	features := &descriptorpb.FeatureSet{EnumType: descriptorpb.FeatureSet_CLOSED.Enum()}
+	proto.SetExtension(features, gofeaturespb.E_Go, &gofeaturespb.GoFeatures{LegacyUnmarshalJsonEnum: proto.Bool(true)})
+	for xt, v := range protoiter.EachPopulatedExtension(features.ProtoReflect(), nil) {
+		fmt.Println(xt.TypeDescriptor().FullName(), xt == gofeaturespb.E_Go, v.Message().Interface())
+	}
+	// Output:
+	// pb.go true legacy_unmarshal_json_enum:true
+}
+
+func TestEachPopulatedExtension(t *testing.T) {
+	files := newFiles(extProto)
+	md := results.Must1(files.FindDescriptorByName("test.Base")).(protoreflect.MessageDescriptor)
+	own := newExtensionTypes(files, "test.a", "test.b")
+	m := dynamicpb.NewMessage(md)
+	for xt := range protoiter.EachExtension(own) {
+		m.Set(xt.TypeDescriptor(), xt.New())
+	}
+	m.SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 102, protowire.VarintType), 1))
+
+	collect := func(types protoiter.Types) map[protoreflect.FullName]protoreflect.ExtensionType {
+		got := make(map[protoreflect.FullName]protoreflect.ExtensionType)
+		for xt := range protoiter.EachPopulatedExtension(m, types) {
+			got[xt.TypeDescriptor().FullName()] = xt
+		}
+		return got
+	}
+	got := collect(nil)
+	if len(got) != 2 {
+		t.Errorf("the populated extensions must be yielded, got %v", got)
+	}
+	for name, xt := range got {
+		if want := results.Must1(own.FindExtensionByName(name)); xt != want {
+			t.Errorf("%s must be yielded with its own type", name)
+		}
+	}
+
+	registered := newExtensionTypes(files, "test.a")
+	got = collect(registered)
+	if xt := got["test.a"]; xt != results.Must1(registered.FindExtensionByName("test.a")) {
+		t.Error("test.a must be resolved in types")
+	}
+	if xt := got["test.b"]; xt != results.Must1(own.FindExtensionByName("test.b")) {
+		t.Error("test.b is not registered and must be yielded with its own type")
+	}
+
+	// A dynamic FeatureSet holding pb.go as a dynamic extension, resolved to the generated gofeaturespb.E_Go.
+	features := dynamicpb.NewMessage((*descriptorpb.FeatureSet)(nil).ProtoReflect().Descriptor())
+	dynamicGo := dynamicpb.NewExtensionType(gofeaturespb.E_Go.TypeDescriptor().Descriptor())
+	goFeatures := dynamicGo.New().Message()
+	goFeatures.Set(goFeatures.Descriptor().Fields().ByName("legacy_unmarshal_json_enum"), protoreflect.ValueOfBool(true))
+	features.Set(dynamicGo.TypeDescriptor(), protoreflect.ValueOfMessage(goFeatures))
+	found := false
+	for xt, v := range protoiter.EachPopulatedExtension(features, protoregistry.GlobalTypes) {
+		found = true
+		if xt != gofeaturespb.E_Go {
+			t.Fatalf("pb.go must be resolved to the generated type, got %T", xt)
+		}
+		got, ok := xt.InterfaceOf(v).(*gofeaturespb.GoFeatures)
+		if !ok || !got.GetLegacyUnmarshalJsonEnum() {
+			t.Errorf("the value must be converted to the generated type, got %v", got)
+		}
+	}
+	if !found {
+		t.Error("pb.go must be yielded")
+	}
+
+	for range protoiter.EachPopulatedExtension(dynamicpb.NewMessage(md), nil) {
+		t.Error("no extension is populated")
+	}
+	for range protoiter.EachPopulatedExtension(m, nil) {
+		break
+	}
+}
+
+func ExampleEachExtensionByMessageSorted() {
+	types := newExtensionTypes(newFiles(extProto), "test.b", "test.a")
+	for xt := range protoiter.EachExtensionByMessageSorted(types, "test.Base") {
+		fmt.Println(xt.TypeDescriptor().Number(), xt.TypeDescriptor().FullName())
+	}
+	// Output:
+	// 100 test.a
+	// 101 test.b
+}
+
+func TestEachOutOfRangeExtension(t *testing.T) {
+	types := newExtensionTypes(newFiles(extProto), "test.a", "test.b")
+	var numbers []protoreflect.FieldNumber
+	for n, xt := range protoiter.EachExtensionNumber(types, "test.Base") {
+		if xt.TypeDescriptor().Number() != n {
+			t.Errorf("%d: mismatched number %d", n, xt.TypeDescriptor().Number())
+		}
+		numbers = append(numbers, n)
+	}
+	if fmt.Sprint(numbers) != "[100 101]" {
+		t.Errorf("numbers %v", numbers)
+	}
+
+	// A newer version of test.Base that narrowed its extension range to [100, 101).
+	narrowed := newFiles(`
+		name: "ext.proto"
+		package: "test"
+		message_type { name: "Base" extension_range { start: 100 end: 101 } }
+	`)
+	md := results.Must1(narrowed.FindDescriptorByName("test.Base")).(protoreflect.MessageDescriptor)
+	found := 0
+	for n, xt := range protoiter.EachOutOfRangeExtension(types, md) {
+		found++
+		if n != 101 || xt.TypeDescriptor().FullName() != "test.b" {
+			t.Errorf("unexpected out-of-range extension %d %v", n, xt.TypeDescriptor().FullName())
+		}
+	}
+	if found != 1 {
+		t.Errorf("found %d out-of-range extensions, want 1", found)
+	}
+	for range protoiter.EachExtensionNumber(types, "test.Base") {
+		break
+	}
+}
+
+// mergedTypes is a Types ranging over all types of several registries, including duplicates.
+type mergedTypes []*protoregistry.Types
+
+func (m mergedTypes) RangeEnums(f func(protoreflect.EnumType) bool) {
+	for _, types := range m {
+		types.RangeEnums(f)
+	}
+}
+
+func (m mergedTypes) RangeMessages(f func(protoreflect.MessageType) bool) {
+	for _, types := range m {
+		types.RangeMessages(f)
+	}
+}
+
+func (m mergedTypes) RangeExtensions(f func(protoreflect.ExtensionType) bool) {
+	for _, types := range m {
+		types.RangeExtensions(f)
+	}
+}
+
+func (m mergedTypes) RangeExtensionsByMessage(message protoreflect.FullName, f func(protoreflect.ExtensionType) bool) {
+	for _, types := range m {
+		types.RangeExtensionsByMessage(message, f)
+	}
+}
+
+func ExampleEachExtensionConflict() {
+	files := newFiles(extProto, `
+		name: "plugin.proto"
+		package: "plugin"
+		dependency: "ext.proto"
+		extension { name: "tag" number: 101 label: LABEL_OPTIONAL type: TYPE_STRING extendee: ".test.Base" }
+		extension { name: "note" number: 102 label: LABEL_OPTIONAL type: TYPE_STRING extendee: ".test.Base" }
+	`)
+	types := mergedTypes{
+		newExtensionTypes(files, "test.a", "test.b"),
+		newExtensionTypes(files, "test.a", "plugin.tag", "plugin.note"),
+	}
+	for x, y := range protoiter.EachExtensionConflict(types) {
+		fmt.Println(x.TypeDescriptor().Number(), x.TypeDescriptor().FullName(), y.TypeDescriptor().FullName())
+	}
+	// Output:
+	// 101 plugin.tag test.b
+}
+
+func TestEachExtensionConflict(t *testing.T) {
+	files := newFiles(extProto)
+	for x, y := range protoiter.EachExtensionConflict(newExtensionTypes(files, "test.a", "test.b")) {
+		t.Errorf("unexpected conflict %v %v", x.TypeDescriptor().FullName(), y.TypeDescriptor().FullName())
+	}
+	types := mergedTypes{newExtensionTypes(files, "test.a"), newExtensionTypes(files, "test.a")}
+	for x, y := range protoiter.EachExtensionConflict(types) {
+		t.Errorf("duplicates must not conflict: %v %v", x.TypeDescriptor().FullName(), y.TypeDescriptor().FullName())
+	}
+}
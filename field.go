@@ -0,0 +1,187 @@
+package protoiter
+
+import (
+	"iter"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachFieldCloned is like [EachField] but yields defensive copies of composite values.
+//
+// Message values are deep copies, and list and map values are fresh containers holding deep copies of their elements,
+// so the yielded values may be retained and modified after the loop without aliasing the original message.
+// Scalar values are immutable and yielded as they are.
+//
+// Parameters:
+//   - message: The protocol buffer message to iterate over
+//
+// Returns:
+//   - An iterator sequence that yields each field descriptor and a copy of its value
+func EachFieldCloned(message protoreflect.Message) iter.Seq2[protoreflect.FieldDescriptor, protoreflect.Value] {
+	return func(yield func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+		message.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			return yield(fd, cloneValue(message, fd, v))
+		})
+	}
+}
+
+// EachFieldMutable is like [EachField] but allows the message to be mutated arbitrarily during iteration.
+//
+// [protoreflect.Message.Range] only permits mutating the current field, so EachFieldMutable first takes a snapshot
+// of the populated field descriptors and then yields them with their current values.
+// Any field may be set or cleared inside the loop, e.g. by a redaction pass;
+// a field cleared before its turn is skipped, and a field populated during iteration is not visited.
+//
+// Parameters:
+//   - message: The protocol buffer message to iterate over
+//
+// Returns:
+//   - An iterator sequence that yields each field descriptor and its corresponding value
+func EachFieldMutable(message protoreflect.Message) iter.Seq2[protoreflect.FieldDescriptor, protoreflect.Value] {
+	return func(yield func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+		yieldFields(message, populatedFields(message), yield)
+	}
+}
+
+// FieldNumbersSet creates a sequential iterator over the numbers of the populated fields of a message, in ascending order.
+//
+// Populated extension fields are included.
+//
+// Parameters:
+//   - message: The protocol buffer message to inspect
+//
+// Returns:
+//   - An iterator sequence that yields each populated field number
+func FieldNumbersSet(message protoreflect.Message) iter.Seq[protoreflect.FieldNumber] {
+	return func(yield func(protoreflect.FieldNumber) bool) {
+		var numbers []protoreflect.FieldNumber
+		message.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			numbers = append(numbers, fd.Number())
+			return true
+		})
+		slices.Sort(numbers)
+		for _, n := range numbers {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// FieldValue is the value of a field together with whether the field is populated.
+type FieldValue struct {
+	// Value is the value of the field, or its default value if the field is not populated.
+	Value protoreflect.Value
+
+	// Populated reports whether the field is populated, as reported by [protoreflect.Message.Has].
+	Populated bool
+}
+
+// EachFieldIn creates a sequential iterator over selected fields of a message, whether they are populated or not.
+//
+// The fields are yielded in the order of the given numbers.
+// Numbers that do not identify a field declared in the message descriptor are skipped;
+// extension fields cannot be selected.
+//
+// Parameters:
+//   - message: The protocol buffer message to inspect
+//   - numbers: The numbers of the fields to yield
+//
+// Returns:
+//   - An iterator sequence that yields each selected field descriptor and its value with presence
+func EachFieldIn(message protoreflect.Message, numbers ...protoreflect.FieldNumber) iter.Seq2[protoreflect.FieldDescriptor, FieldValue] {
+	return func(yield func(protoreflect.FieldDescriptor, FieldValue) bool) {
+		fields := message.Descriptor().Fields()
+		for _, n := range numbers {
+			fd := fields.ByNumber(n)
+			if fd == nil {
+				continue
+			}
+			if !yield(fd, FieldValue{Value: message.Get(fd), Populated: message.Has(fd)}) {
+				return
+			}
+		}
+	}
+}
+
+// EachFieldDeclared creates a sequential iterator over all fields declared in the descriptor of a message,
+// whether they are populated or not.
+//
+// The fields are yielded in declaration order with their values, or with their default values if they are unset,
+// so exporters can emit a complete record for each message: a scalar yields its declared or zero default,
+// a repeated or map field an empty read-only list or map, and a message field an empty read-only message.
+// Each member of a oneof is yielded, with only the member that is set, if any, populated.
+// Extension fields are not declared in the message descriptor and are not yielded.
+//
+// Parameters:
+//   - message: The protocol buffer message to inspect
+//
+// Returns:
+//   - An iterator sequence that yields each declared field descriptor and its value with presence
+func EachFieldDeclared(message protoreflect.Message) iter.Seq2[protoreflect.FieldDescriptor, FieldValue] {
+	return func(yield func(protoreflect.FieldDescriptor, FieldValue) bool) {
+		fields := message.Descriptor().Fields()
+		for i := range fields.Len() {
+			fd := fields.Get(i)
+			if !yield(fd, FieldValue{Value: message.Get(fd), Populated: message.Has(fd)}) {
+				return
+			}
+		}
+	}
+}
+
+// populatedFields returns the descriptors of the populated fields of message in [protoreflect.Message.Range] order.
+func populatedFields(message protoreflect.Message) []protoreflect.FieldDescriptor {
+	var fields []protoreflect.FieldDescriptor
+	message.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	return fields
+}
+
+// yieldFields yields the fields that are still populated with their current values.
+func yieldFields(message protoreflect.Message, fields []protoreflect.FieldDescriptor, yield func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+	for _, fd := range fields {
+		if !message.Has(fd) {
+			continue
+		}
+		if !yield(fd, message.Get(fd)) {
+			return
+		}
+	}
+}
+
+// cloneValue returns a deep copy of the value v of the field fd in message.
+func cloneValue(message protoreflect.Message, fd protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+	switch {
+	case fd.IsList():
+		src := v.List()
+		dst := message.New().Mutable(fd).List()
+		for i := range src.Len() {
+			dst.Append(cloneElement(fd, src.Get(i)))
+		}
+		return protoreflect.ValueOfList(dst)
+	case fd.IsMap():
+		dst := message.New().Mutable(fd).Map()
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			dst.Set(k, cloneElement(fd.MapValue(), v))
+			return true
+		})
+		return protoreflect.ValueOfMap(dst)
+	}
+	return cloneElement(fd, v)
+}
+
+// cloneElement returns a deep copy of a singular value of the field fd.
+func cloneElement(fd protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+	switch {
+	case fd.Message() != nil:
+		return protoreflect.ValueOfMessage(proto.Clone(v.Message().Interface()).ProtoReflect())
+	case fd.Kind() == protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(append([]byte(nil), v.Bytes()...))
+	}
+	return v
+}
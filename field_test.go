@@ -0,0 +1,170 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleEachFieldCloned() {
+	m := &descriptorpb.DescriptorProto{Options: &descriptorpb.MessageOptions{Deprecated: proto.Bool(true)}}
+	var kept []protoreflect.Value
+	for _, value := range protoiter.EachFieldCloned(m.ProtoReflect()) {
+		kept = append(kept, value)
+	}
+	m.Options.Deprecated = proto.Bool(false)
+	fmt.Println(kept[0].Message().Interface().(*descriptorpb.MessageOptions).GetDeprecated())
+	// Output:
+	// true
+}
+
+func TestEachFieldCloned(t *testing.T) {
+	s := results.Must1(structpb.NewStruct(map[string]any{"a": "x"}))
+	l := results.Must1(structpb.NewList([]any{"y"}))
+	loc := &descriptorpb.SourceCodeInfo_Location{Path: []int32{1}}
+	values := make(map[string]protoreflect.Value)
+	for _, m := range []proto.Message{s, l, loc} {
+		for field, value := range protoiter.EachFieldCloned(m.ProtoReflect()) {
+			values[string(field.Name())] = value
+		}
+	}
+	s.Fields["a"].Kind = &structpb.Value_StringValue{StringValue: "changed"}
+	l.Values[0].Kind = &structpb.Value_StringValue{StringValue: "changed"}
+	loc.Path[0] = 2
+
+	if got := values["fields"].Map().Get(protoreflect.ValueOfString("a").MapKey()).Message().Interface().(*structpb.Value).GetStringValue(); got != "x" {
+		t.Errorf("map values must be copies, got %q", got)
+	}
+	if got := values["values"].List().Get(0).Message().Interface().(*structpb.Value).GetStringValue(); got != "y" {
+		t.Errorf("list elements must be copies, got %q", got)
+	}
+	if got := values["path"].List().Get(0).Int(); got != 1 {
+		t.Errorf("lists must be copies, got %d", got)
+	}
+}
+
+func ExampleEachFieldMutable() {
+	m := &descriptorpb.FieldDescriptorProto{
+		Name:         proto.String("password"),
+		DefaultValue: proto.String("hunter2"),
+		JsonName:     proto.String("password"),
+	}
+	for field := range protoiter.EachFieldMutable(m.ProtoReflect()) {
+		if field.Name() == "name" {
+			// Redact every other field, not just the current one.
+			m.ProtoReflect().Clear(m.ProtoReflect().Descriptor().Fields().ByName("default_value"))
+		}
+	}
+	fmt.Println(m.GetName(), m.DefaultValue == nil)
+	// Output:
+	// password true
+}
+
+func TestEachFieldMutable(t *testing.T) {
+	m := &descriptorpb.FieldDescriptorProto{
+		Name:         proto.String("a"),
+		DefaultValue: proto.String("b"),
+		JsonName:     proto.String("c"),
+	}
+	fields := m.ProtoReflect().Descriptor().Fields()
+	var seen []protoreflect.Name
+	for field := range protoiter.EachFieldMutable(m.ProtoReflect()) {
+		seen = append(seen, field.Name())
+		m.ProtoReflect().Clear(fields.ByName("name"))
+		m.ProtoReflect().Clear(fields.ByName("default_value"))
+		m.ProtoReflect().Clear(fields.ByName("json_name"))
+		m.ProtoReflect().Set(fields.ByName("number"), protoreflect.ValueOfInt32(1))
+	}
+	if len(seen) != 1 {
+		t.Errorf("cleared fields must be skipped and new fields not visited, got %v", seen)
+	}
+	if m.GetNumber() != 1 {
+		t.Error("must keep mutations")
+	}
+}
+
+func ExampleFieldNumbersSet() {
+	m := &descriptorpb.FieldDescriptorProto{
+		JsonName: proto.String("id"),
+		Name:     proto.String("id"),
+		Number:   proto.Int32(1),
+	}
+	fmt.Println(slices.Collect(protoiter.FieldNumbersSet(m.ProtoReflect())))
+	// Output:
+	// [1 3 10]
+}
+
+func ExampleEachFieldIn() {
+	m := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), Number: proto.Int32(1)}
+	for field, v := range protoiter.EachFieldIn(m.ProtoReflect(), 3, 1, 99, 10) {
+		fmt.Println(field.Name(), v.Value, v.Populated)
+	}
+	// Output:
+	// number 1 true
+	// name id true
+	// json_name  false
+}
+
+func ExampleEachFieldDeclared() {
+	m := &durationpb.Duration{Seconds: 5}
+	for field, v := range protoiter.EachFieldDeclared(m.ProtoReflect()) {
+		fmt.Println(field.Name(), v.Value, v.Populated)
+	}
+	// Output:
+	// seconds 5 true
+	// nanos 0 false
+}
+
+func TestEachFieldDeclared(t *testing.T) {
+	files := newFiles(`
+		name: "declared.proto"
+		package: "test"
+		message_type {
+			name: "M"
+			field { name: "count" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 default_value: "7" }
+			field { name: "tags" number: 1 label: LABEL_REPEATED type: TYPE_STRING }
+			field { name: "child" number: 3 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.M" }
+			field { name: "a" number: 4 label: LABEL_OPTIONAL type: TYPE_STRING oneof_index: 0 }
+			field { name: "b" number: 5 label: LABEL_OPTIONAL type: TYPE_STRING oneof_index: 0 }
+			oneof_decl { name: "choice" }
+		}
+	`)
+	md := results.Must1(files.FindDescriptorByName("test.M")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	results.Must(prototext.Unmarshal([]byte(`b: "x"`), m))
+
+	var got []string
+	for fd, v := range protoiter.EachFieldDeclared(m) {
+		var s string
+		switch {
+		case fd.IsList():
+			s = fmt.Sprint(v.Value.List().Len())
+		case fd.Message() != nil:
+			s = fmt.Sprint(v.Value.Message().IsValid())
+		default:
+			s = v.Value.String()
+		}
+		got = append(got, fmt.Sprintf("%s=%s/%t", fd.Name(), s, v.Populated))
+	}
+	want := []string{"count=7/false", "tags=0/false", "child=false/false", "a=/false", "b=x/true"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	for fd := range protoiter.EachFieldDeclared(m) {
+		if fd.Name() != "count" {
+			t.Errorf("iteration must stop at the first field, got %v", fd.Name())
+		}
+		break
+	}
+}
@@ -0,0 +1,56 @@
+package protoiter
+
+import (
+	"iter"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachFieldByCardinality creates a sequential iterator over the fields of a message descriptor grouped by cardinality.
+//
+// All optional fields are yielded first, then the required fields, then the repeated fields (including maps),
+// each group in declaration order, so generators that emit a different code path per cardinality
+// can handle each group in one pass without repeated filtering.
+//
+// Parameters:
+//   - md: The message descriptor whose fields are iterated
+//
+// Returns:
+//   - An iterator sequence that yields the cardinality and descriptor of each field
+func EachFieldByCardinality(md protoreflect.MessageDescriptor) iter.Seq2[protoreflect.Cardinality, protoreflect.FieldDescriptor] {
+	return func(yield func(protoreflect.Cardinality, protoreflect.FieldDescriptor) bool) {
+		fields := md.Fields()
+		for _, c := range []protoreflect.Cardinality{protoreflect.Optional, protoreflect.Required, protoreflect.Repeated} {
+			for i := range fields.Len() {
+				fd := fields.Get(i)
+				if fd.Cardinality() == c && !yield(c, fd) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// EachWeakField creates a sequential iterator over the weak fields of a message descriptor.
+//
+// Weak fields are a deprecated proto2 feature, declared with the weak field option, that lets a message
+// refer to a type without a hard dependency on it. Inventorying them helps migrate code away from weak fields.
+// Note that [google.golang.org/protobuf/reflect/protodesc] rejects weak fields, so they only appear in descriptors
+// built by other means, such as old generated code.
+// The fields are yielded in declaration order.
+//
+// Parameters:
+//   - md: The message descriptor whose fields are iterated
+//
+// Returns:
+//   - An iterator sequence that yields each field reporting [protoreflect.FieldDescriptor.IsWeak]
+func EachWeakField(md protoreflect.MessageDescriptor) iter.Seq[protoreflect.FieldDescriptor] {
+	return func(yield func(protoreflect.FieldDescriptor) bool) {
+		fields := md.Fields()
+		for i := range fields.Len() {
+			if fd := fields.Get(i); fd.IsWeak() && !yield(fd) {
+				return
+			}
+		}
+	}
+}
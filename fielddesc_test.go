@@ -0,0 +1,93 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleEachFieldByCardinality() {
+	md := (&descriptorpb.UninterpretedOption{}).ProtoReflect().Descriptor()
+	for c, fd := range protoiter.EachFieldByCardinality(md) {
+		fmt.Println(c, fd.Name())
+	}
+	// Output:
+	// optional identifier_value
+	// optional positive_int_value
+	// optional negative_int_value
+	// optional double_value
+	// optional string_value
+	// optional aggregate_value
+	// repeated name
+}
+
+func TestEachFieldByCardinality(t *testing.T) {
+	md := (&descriptorpb.UninterpretedOption_NamePart{}).ProtoReflect().Descriptor()
+	var got []string
+	for c, fd := range protoiter.EachFieldByCardinality(md) {
+		got = append(got, fmt.Sprint(c, " ", fd.Name()))
+	}
+	if fmt.Sprint(got) != "[required name_part required is_extension]" {
+		t.Errorf("got %v", got)
+	}
+	for range protoiter.EachFieldByCardinality(md) {
+		break
+	}
+}
+
+func TestEachWeakField(t *testing.T) {
+	// protodesc no longer accepts weak fields, so descriptors with them are simulated by weakMessage.
+	for _, m := range []proto.Message{&descriptorpb.FieldOptions{}, &structpb.Struct{}} {
+		for fd := range protoiter.EachWeakField(m.ProtoReflect().Descriptor()) {
+			t.Errorf("unexpected weak field %v", fd.FullName())
+		}
+	}
+
+	md := weakMessage{(&descriptorpb.FieldOptions{}).ProtoReflect().Descriptor(), []protoreflect.Name{"weak", "ctype"}}
+	var got []protoreflect.Name
+	for fd := range protoiter.EachWeakField(md) {
+		got = append(got, fd.Name())
+	}
+	if want := []protoreflect.Name{"ctype", "weak"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	for range protoiter.EachWeakField(md) {
+		break
+	}
+}
+
+// weakMessage is a message descriptor whose named fields report being weak,
+// as descriptors of old generated code may.
+type weakMessage struct {
+	protoreflect.MessageDescriptor
+	weak []protoreflect.Name
+}
+
+func (m weakMessage) Fields() protoreflect.FieldDescriptors {
+	return weakFields{m.MessageDescriptor.Fields(), m.weak}
+}
+
+type weakFields struct {
+	protoreflect.FieldDescriptors
+	weak []protoreflect.Name
+}
+
+func (f weakFields) Get(i int) protoreflect.FieldDescriptor {
+	fd := f.FieldDescriptors.Get(i)
+	if slices.Contains(f.weak, fd.Name()) {
+		return weakField{fd}
+	}
+	return fd
+}
+
+type weakField struct {
+	protoreflect.FieldDescriptor
+}
+
+func (weakField) IsWeak() bool { return true }
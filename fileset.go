@@ -0,0 +1,102 @@
+package protoiter
+
+import (
+	"io"
+	"iter"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// FileSetOptions configures [WriteFileDescriptorSet].
+type FileSetOptions struct {
+	// IncludeImports adds the transitive imports of the files to the set, like protoc --include_imports,
+	// making the set self-contained.
+	IncludeImports bool
+
+	// ExcludeSourceInfo drops the source code info, including comments, from the files.
+	ExcludeSourceInfo bool
+}
+
+// WriteFileDescriptorSet writes a sequence of files to w as a serialized FileDescriptorSet.
+//
+// Each file is written once, even if it occurs more than once, and after the files it imports,
+// so the set can be loaded with [protodesc.NewFiles] or by protoc. Placeholder files are skipped.
+// The set is marshaled deterministically. It is the export counterpart of [EachFileInSet].
+//
+// Parameters:
+//   - w: The writer receiving the serialized set
+//   - seq: The files to write, e.g. from [EachFile] or a query
+//   - opts: Whether to include imports and source info
+//
+// Returns:
+//   - The first marshaling or write error, if any
+func WriteFileDescriptorSet(w io.Writer, seq iter.Seq[protoreflect.FileDescriptor], opts FileSetOptions) error {
+	set := new(descriptorpb.FileDescriptorSet)
+	selected := make(map[string]bool)
+	var order []protoreflect.FileDescriptor
+	for fd := range seq {
+		if !selected[fd.Path()] {
+			selected[fd.Path()] = true
+			order = append(order, fd)
+		}
+	}
+	visited := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if visited[fd.Path()] {
+			return
+		}
+		visited[fd.Path()] = true
+		imports := fd.Imports()
+		for i := range imports.Len() {
+			add(imports.Get(i).FileDescriptor)
+		}
+		if fd.IsPlaceholder() || !(selected[fd.Path()] || opts.IncludeImports) {
+			return
+		}
+		fdp := protodesc.ToFileDescriptorProto(fd)
+		if opts.ExcludeSourceInfo {
+			fdp.SourceCodeInfo = nil
+		}
+		set.File = append(set.File, fdp)
+	}
+	for _, fd := range order {
+		add(fd)
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(set)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// EachFileInSet creates a sequential iterator over the files of a FileDescriptorSet.
+//
+// The set is resolved with [protodesc.NewFiles], so it must be self-contained, e.g. written by
+// [WriteFileDescriptorSet] with IncludeImports or by protoc with --include_imports.
+// If the set cannot be resolved, the iterator yields nil with the error and stops.
+//
+// Parameters:
+//   - set: The FileDescriptorSet to resolve
+//
+// Returns:
+//   - An iterator sequence that yields each file descriptor in the order of the set, or an error
+func EachFileInSet(set *descriptorpb.FileDescriptorSet) iter.Seq2[protoreflect.FileDescriptor, error] {
+	return func(yield func(protoreflect.FileDescriptor, error) bool) {
+		files, err := protodesc.NewFiles(set)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, fdp := range set.GetFile() {
+			fd, err := files.FindFileByPath(fdp.GetName())
+			if !yield(fd, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package protoiter_test
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func ExampleWriteFileDescriptorSet() {
+	var _ emptypb.Empty
+	files := newFiles(extProto, serviceProto)
+	var buf bytes.Buffer
+	seq := protoiter.OfType[protoreflect.FileDescriptor](protoiter.EachQuery(files, "file(service.proto)"))
+	results.Must(protoiter.WriteFileDescriptorSet(&buf, seq, protoiter.FileSetOptions{IncludeImports: true}))
+	set := new(descriptorpb.FileDescriptorSet)
+	results.Must(proto.Unmarshal(buf.Bytes(), set))
+	for fd, err := range protoiter.EachFileInSet(set) {
+		fmt.Println(fd.Path(), err)
+	}
+	// Output:
+	// google/protobuf/empty.proto <nil>
+	// ext.proto <nil>
+	// service.proto <nil>
+}
+
+func TestWriteFileDescriptorSet(t *testing.T) {
+	files := newFiles(extProto, serviceProto)
+	service := results.Must1(files.FindFileByPath("service.proto"))
+	ext := results.Must1(files.FindFileByPath("ext.proto"))
+	paths := func(opts protoiter.FileSetOptions, fds ...protoreflect.FileDescriptor) []string {
+		var buf bytes.Buffer
+		results.Must(protoiter.WriteFileDescriptorSet(&buf, slices.Values(fds), opts))
+		set := new(descriptorpb.FileDescriptorSet)
+		results.Must(proto.Unmarshal(buf.Bytes(), set))
+		var paths []string
+		for _, fdp := range set.GetFile() {
+			paths = append(paths, fdp.GetName())
+			if opts.ExcludeSourceInfo && fdp.SourceCodeInfo != nil {
+				t.Errorf("%s: source info must be excluded", fdp.GetName())
+			}
+		}
+		return paths
+	}
+	if got, want := paths(protoiter.FileSetOptions{}, service, ext, service), []string{"ext.proto", "service.proto"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	if got, want := paths(protoiter.FileSetOptions{IncludeImports: true, ExcludeSourceInfo: true}, service), []string{"google/protobuf/empty.proto", "ext.proto", "service.proto"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	// A set lacking imports cannot be resolved.
+	var buf bytes.Buffer
+	results.Must(protoiter.WriteFileDescriptorSet(&buf, slices.Values([]protoreflect.FileDescriptor{service}), protoiter.FileSetOptions{}))
+	set := new(descriptorpb.FileDescriptorSet)
+	results.Must(proto.Unmarshal(buf.Bytes(), set))
+	for fd, err := range protoiter.EachFileInSet(set) {
+		if fd != nil || err == nil {
+			t.Errorf("got %v, %v; want an error", fd, err)
+		}
+	}
+}
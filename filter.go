@@ -0,0 +1,50 @@
+package protoiter
+
+import (
+	"iter"
+
+	"github.com/goaux/protoiter/internal/reflectutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OfType creates a sequential iterator over the descriptors of a sequence that are of the concrete descriptor type T.
+//
+// It narrows the output of generic walkers such as [EachQuery] or [EachUnreferenced],
+// performing the type assertion once inside the adapter instead of in every loop body.
+// Note that extensions are [protoreflect.FieldDescriptor] values too, and are kept when T is FieldDescriptor.
+//
+// Parameters:
+//   - seq: The sequence of descriptors to filter
+//
+// Returns:
+//   - An iterator sequence that yields each descriptor of type T
+func OfType[T protoreflect.Descriptor](seq iter.Seq[protoreflect.Descriptor]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for d := range seq {
+			if t, ok := d.(T); ok && !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// EachLabeled creates a sequential iterator over the descriptors of a sequence with a label naming the kind of each.
+//
+// The labels are the kinds of a [Query]: "file", "message", "field", "oneof", "enum", "enum_value",
+// "extension", "service" and "method". They are stable, so consumers of generic walkers can switch on them
+// and log descriptors uniformly without a type switch. A descriptor of an unknown implementation is labeled "".
+//
+// Parameters:
+//   - seq: The sequence of descriptors to label
+//
+// Returns:
+//   - An iterator sequence that yields each label and descriptor
+func EachLabeled(seq iter.Seq[protoreflect.Descriptor]) iter.Seq2[string, protoreflect.Descriptor] {
+	return func(yield func(string, protoreflect.Descriptor) bool) {
+		for d := range seq {
+			if !yield(reflectutil.Kind(d), d) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func ExampleOfType() {
+	files := newFiles(acmeProto)
+	for fd := range protoiter.OfType[protoreflect.FieldDescriptor](protoiter.EachQuery(files, "*")) {
+		fmt.Println(fd.FullName(), fd.Kind())
+	}
+	// Output:
+	// acme.store.Blob.id string
+	// acme.store.Blob.data bytes
+	// acme.store.Blob.parts bytes
+	// acme.store.Blob.Meta.digest bytes
+	// acme.store.Blob.Meta.state enum
+}
+
+func TestOfType(t *testing.T) {
+	files := newFiles(acmeProto)
+	all := protoiter.EachQuery(files, "*")
+	if n := len(slices.Collect(protoiter.OfType[protoreflect.ServiceDescriptor](all))); n != 1 {
+		t.Errorf("services: %d", n)
+	}
+	if n := len(slices.Collect(protoiter.OfType[protoreflect.Descriptor](all))); n != len(slices.Collect(all)) {
+		t.Errorf("Descriptor must keep everything, got %d", n)
+	}
+	for range protoiter.OfType[protoreflect.MessageDescriptor](all) {
+		break
+	}
+}
+
+func ExampleEachLabeled() {
+	files := newFiles(acmeProto)
+	for label, d := range protoiter.EachLabeled(protoiter.EachQuery(files, "message(acme.store.Blob.Meta)/*")) {
+		fmt.Println(label, d.Name())
+	}
+	// Output:
+	// field digest
+	// field state
+}
+
+func TestEachLabeled(t *testing.T) {
+	files := newFiles(acmeProto, extProto)
+	counts := make(map[string]int)
+	for label, d := range protoiter.EachLabeled(protoiter.EachQuery(files, "*")) {
+		counts[label]++
+		if label == "" {
+			t.Errorf("%v: missing label", d.FullName())
+		}
+	}
+	for _, label := range []string{"file", "message", "field", "enum", "enum_value", "extension", "service", "method"} {
+		if counts[label] == 0 {
+			t.Errorf("no %s labels in %v", label, counts)
+		}
+	}
+	for range protoiter.EachLabeled(protoiter.EachQuery(files, "*")) {
+		break
+	}
+}
@@ -0,0 +1,69 @@
+package protoiter
+
+import (
+	"fmt"
+	"io/fs"
+	"iter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// EachTextprotoFile creates a sequential iterator over messages parsed from text-format files in a file system.
+//
+// The files matching the pattern, as reported by [fs.Glob], are read in lexical order,
+// and each is parsed with [prototext.Unmarshal] into a message created by newM.
+// If a file cannot be read or parsed, the message and an error naming the file are yielded and iteration continues;
+// an invalid pattern is yielded as an error with a zero message.
+//
+// Parameters:
+//   - fsys: The file system holding the fixtures, e.g. an embed.FS or the result of os.DirFS
+//   - pattern: The path.Match pattern selecting the files, e.g. "testdata/*.textpb"
+//   - newM: A function returning a new empty message for each file
+//
+// Returns:
+//   - An iterator sequence that yields each parsed message, or an error
+func EachTextprotoFile[M proto.Message](fsys fs.FS, pattern string, newM func() M) iter.Seq2[M, error] {
+	return eachFile(fsys, pattern, newM, prototext.Unmarshal)
+}
+
+// EachJSONFile creates a sequential iterator over messages parsed from protojson files in a file system.
+//
+// It is the protojson counterpart of [EachTextprotoFile]: the files matching the pattern are read in lexical order,
+// each is parsed with [protojson.Unmarshal], and per-file errors name the file.
+//
+// Parameters:
+//   - fsys: The file system holding the fixtures, e.g. an embed.FS or the result of os.DirFS
+//   - pattern: The path.Match pattern selecting the files, e.g. "testdata/*.json"
+//   - newM: A function returning a new empty message for each file
+//
+// Returns:
+//   - An iterator sequence that yields each parsed message, or an error
+func EachJSONFile[M proto.Message](fsys fs.FS, pattern string, newM func() M) iter.Seq2[M, error] {
+	return eachFile(fsys, pattern, newM, protojson.Unmarshal)
+}
+
+func eachFile[M proto.Message](fsys fs.FS, pattern string, newM func() M, unmarshal func([]byte, proto.Message) error) iter.Seq2[M, error] {
+	return func(yield func(M, error) bool) {
+		names, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			var zero M
+			yield(zero, err)
+			return
+		}
+		for _, name := range names {
+			m := newM()
+			b, err := fs.ReadFile(fsys, name)
+			if err == nil {
+				err = unmarshal(b, m)
+			}
+			if err != nil {
+				err = fmt.Errorf("protoiter: %s: %w", name, err)
+			}
+			if !yield(m, err) {
+				return
+			}
+		}
+	}
+}
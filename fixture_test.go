@@ -0,0 +1,75 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func ExampleEachTextprotoFile() {
+	fsys := fstest.MapFS{
+		"testdata/a.textpb": {Data: []byte("seconds: 1")},
+		"testdata/b.textpb": {Data: []byte("seconds: 2 nanos: 3")},
+		"testdata/c.json":   {Data: []byte(`{}`)},
+	}
+	newDuration := func() *durationpb.Duration { return new(durationpb.Duration) }
+	for m, err := range protoiter.EachTextprotoFile(fsys, "testdata/*.textpb", newDuration) {
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(m.GetSeconds(), m.GetNanos())
+	}
+	// Output:
+	// 1 0
+	// 2 3
+}
+
+func TestEachTextprotoFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.textpb": {Data: []byte("seconds: 1")},
+		"b.textpb": {Data: []byte("hours: 2")},
+		"c.textpb": {Data: []byte("seconds: 3")},
+	}
+	newDuration := func() *durationpb.Duration { return new(durationpb.Duration) }
+	var got []int64
+	var errs []error
+	for m, err := range protoiter.EachTextprotoFile(fsys, "*.textpb", newDuration) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		got = append(got, m.GetSeconds())
+	}
+	if len(got) != 2 || len(errs) != 1 {
+		t.Errorf("unexpected result %v %v", got, errs)
+	}
+	for _, err := range protoiter.EachTextprotoFile(fsys, "[", newDuration) {
+		if err == nil {
+			t.Error("an invalid pattern must be an error")
+		}
+	}
+}
+
+func ExampleEachJSONFile() {
+	fsys := fstest.MapFS{
+		"testdata/a.json": {Data: []byte(`"1.5s"`)},
+		"testdata/b.json": {Data: []byte(`"2s"`)},
+		"testdata/c.json": {Data: []byte(`{}`)},
+	}
+	newDuration := func() *durationpb.Duration { return new(durationpb.Duration) }
+	for m, err := range protoiter.EachJSONFile(fsys, "testdata/*.json", newDuration) {
+		if err != nil {
+			fmt.Println(strings.Contains(err.Error(), "testdata/c.json"))
+			continue
+		}
+		fmt.Println(m.AsDuration())
+	}
+	// Output:
+	// 1.5s
+	// 2s
+	// true
+}
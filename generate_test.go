@@ -0,0 +1,115 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleGenerateMessages() {
+	md := (&structpb.Struct{}).ProtoReflect().Descriptor()
+	n := 0
+	for m := range protoiter.GenerateMessages(md, 1, 100) {
+		b := results.Must1(proto.Marshal(m))
+		round := m.ProtoReflect().New().Interface()
+		results.Must(proto.Unmarshal(b, round))
+		if proto.Equal(m, round) {
+			n++
+		}
+	}
+	fmt.Println(n, "messages survived a round trip")
+	// Output:
+	// 100 messages survived a round trip
+}
+
+func TestGenerateMessages(t *testing.T) {
+	md := (&descriptorpb.FileDescriptorProto{}).ProtoReflect().Descriptor()
+	var first []proto.Message
+	for m := range protoiter.GenerateMessages(md, 42, 20) {
+		first = append(first, m)
+	}
+	i := 0
+	populated := 0
+	for m := range protoiter.GenerateMessages(md, 42, 20) {
+		if !proto.Equal(m, first[i]) {
+			t.Errorf("message %d differs for the same seed", i)
+		}
+		if proto.Size(m) > 0 {
+			populated++
+		}
+		i++
+	}
+	if i != 20 || populated == 0 {
+		t.Errorf("generated %d messages, %d populated", i, populated)
+	}
+	// Required fields are always set, so a type with required fields at the top level is initialized.
+	part := (&descriptorpb.UninterpretedOption_NamePart{}).ProtoReflect().Descriptor()
+	for m := range protoiter.GenerateMessages(part, 7, 10) {
+		if err := proto.CheckInitialized(m); err != nil {
+			t.Error(err)
+		}
+	}
+	// Below the depth cap, the required fields of uninterpreted_option.name are still set.
+	set := (&descriptorpb.FileDescriptorSet{}).ProtoReflect().Descriptor()
+	for m := range protoiter.GenerateMessages(set, 3, 200) {
+		if err := proto.CheckInitialized(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range protoiter.GenerateMessages(md, 0, 10) {
+		break
+	}
+}
+
+func TestGenerateForAll(t *testing.T) {
+	types := new(protoregistry.Types)
+	for _, m := range []proto.Message{&structpb.Struct{}, &descriptorpb.FileDescriptorSet{}, &durationpb.Duration{}} {
+		results.Must(types.RegisterMessage(m.ProtoReflect().Type()))
+	}
+	var names []protoreflect.FullName
+	for mt, m := range protoiter.GenerateForAll(types, 1, 2) {
+		if m.ProtoReflect().Type() != mt {
+			t.Errorf("%v: instance is not of the registered type", mt.Descriptor().FullName())
+		}
+		b := results.Must1(proto.Marshal(m))
+		round := mt.New().Interface()
+		results.Must(proto.Unmarshal(b, round))
+		if !proto.Equal(m, round) {
+			t.Errorf("%v: round trip changed the message", mt.Descriptor().FullName())
+		}
+		names = append(names, mt.Descriptor().FullName())
+	}
+	want := "[google.protobuf.Duration google.protobuf.Duration google.protobuf.FileDescriptorSet google.protobuf.FileDescriptorSet google.protobuf.Struct google.protobuf.Struct]"
+	if fmt.Sprint(names) != want {
+		t.Errorf("got %v", names)
+	}
+
+	// The instances of a type do not depend on the other registered types.
+	only := new(protoregistry.Types)
+	results.Must(only.RegisterMessage((&structpb.Struct{}).ProtoReflect().Type()))
+	var a, b []proto.Message
+	for mt, m := range protoiter.GenerateForAll(types, 1, 2) {
+		if mt.Descriptor().FullName() == "google.protobuf.Struct" {
+			a = append(a, m)
+		}
+	}
+	for _, m := range protoiter.GenerateForAll(only, 1, 2) {
+		b = append(b, m)
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			t.Errorf("instance %d depends on the registry", i)
+		}
+	}
+	for range protoiter.GenerateForAll(types, 1, 2) {
+		break
+	}
+}
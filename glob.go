@@ -0,0 +1,64 @@
+package protoiter
+
+import (
+	"iter"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachMatching creates a sequential iterator over the descriptors in a registry whose full name matches a glob pattern.
+//
+// In the pattern, "*" matches any run of characters within one name component (it does not cross a "."),
+// "**" matches any run of characters, and every other character matches itself.
+// For example, "acme.*.User" matches acme.v1.User, and "acme.**" matches every descriptor declared in package acme or its subpackages.
+// All descriptor kinds except files are considered.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - pattern: The glob pattern matched against full names
+//
+// Returns:
+//   - An iterator sequence that yields each matching descriptor
+func EachMatching(files Files, pattern string) iter.Seq[protoreflect.Descriptor] {
+	return func(yield func(protoreflect.Descriptor) bool) {
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			if _, ok := d.(protoreflect.FileDescriptor); ok || !matchGlob(pattern, string(d.FullName()), '.') {
+				return true
+			}
+			return yield(d)
+		})
+	}
+}
+
+// matchGlob reports whether name matches pattern,
+// where "*" matches any run of characters other than sep and "**" matches any run of characters.
+func matchGlob(pattern, name string, sep byte) bool {
+	for pattern != "" {
+		switch {
+		case strings.HasPrefix(pattern, "**"):
+			rest := pattern[2:]
+			for i := 0; i <= len(name); i++ {
+				if matchGlob(rest, name[i:], sep) {
+					return true
+				}
+			}
+			return false
+		case pattern[0] == '*':
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchGlob(rest, name[i:], sep) {
+					return true
+				}
+				if i < len(name) && name[i] == sep {
+					return false
+				}
+			}
+			return false
+		case name == "" || pattern[0] != name[0]:
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return name == ""
+}
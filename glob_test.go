@@ -0,0 +1,44 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func ExampleEachMatching() {
+	files := newFiles(acmeProto)
+	for d := range protoiter.EachMatching(files, "acme.*.Blob*") {
+		fmt.Println(d.FullName())
+	}
+	// Output:
+	// acme.store.Blob
+	// acme.store.BlobService
+}
+
+func TestEachMatching(t *testing.T) {
+	files := newFiles(acmeProto)
+	tests := []struct {
+		pattern string
+		want    []protoreflect.FullName
+	}{
+		{"acme.store", nil},
+		{"acme.*", nil},
+		{"**.Meta.*", []protoreflect.FullName{"acme.store.Blob.Meta.digest", "acme.store.Blob.Meta.state"}},
+		{"acme.store.STATE_*", []protoreflect.FullName{"acme.store.STATE_UNSPECIFIED", "acme.store.STATE_READY"}},
+		{"**Get", []protoreflect.FullName{"acme.store.BlobService.Get"}},
+		{"acme.store.Blob.*d*", []protoreflect.FullName{"acme.store.Blob.id", "acme.store.Blob.data"}},
+	}
+	for _, tt := range tests {
+		var got []protoreflect.FullName
+		for d := range protoiter.EachMatching(files, tt.pattern) {
+			got = append(got, d.FullName())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q: must be equal\ngot\t%v\nwant\t%v", tt.pattern, got, tt.want)
+		}
+	}
+}
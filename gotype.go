@@ -0,0 +1,158 @@
+package protoiter
+
+import (
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// EachGoType creates a sequential iterator over the fields of a message descriptor with the Go type each maps to.
+//
+// If md is the descriptor of a generated message registered in [protoregistry.GlobalTypes],
+// the types are read from the fields of its Go struct. Otherwise, and for the members of a oneof,
+// whose struct field is an interface, the types follow the rules of protoc-gen-go:
+//   - scalars map to the Go type of the kind, e.g. int32, uint64, float64, string or []byte
+//   - messages and enums map to their generated types if registered in [protoregistry.GlobalTypes],
+//     otherwise to [proto.Message] and [protoreflect.EnumNumber]
+//   - fields with explicit presence outside a oneof map to a pointer, except for bytes and messages
+//   - repeated fields map to a slice, and map fields to a map
+//
+// Parameters:
+//   - md: The message descriptor whose fields are iterated
+//
+// Returns:
+//   - An iterator sequence that yields each field descriptor and its Go type, in declaration order
+func EachGoType(md protoreflect.MessageDescriptor) iter.Seq2[protoreflect.FieldDescriptor, reflect.Type] {
+	return func(yield func(protoreflect.FieldDescriptor, reflect.Type) bool) {
+		structFields := goStructFields(md)
+		fields := md.Fields()
+		for i := range fields.Len() {
+			fd := fields.Get(i)
+			t, ok := structFields[fd.Number()]
+			if !ok {
+				t = goFieldType(fd)
+			}
+			if !yield(fd, t) {
+				return
+			}
+		}
+	}
+}
+
+// goStructFields returns the types of the struct fields of the generated message md by field number,
+// or nil if md is not a registered generated message. Oneof members are not included.
+func goStructFields(md protoreflect.MessageDescriptor) map[protoreflect.FieldNumber]reflect.Type {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.FullName())
+	if err != nil || mt.Descriptor() != md {
+		return nil
+	}
+	t := goMessageType(mt)
+	if t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	types := make(map[protoreflect.FieldNumber]reflect.Type)
+	for i := range t.Elem().NumField() {
+		sf := t.Elem().Field(i)
+		// The tag is like "varint,1,opt,name=id,proto3".
+		parts := strings.Split(sf.Tag.Get("protobuf"), ",")
+		if len(parts) < 2 {
+			continue
+		}
+		if n, err := strconv.Atoi(parts[1]); err == nil {
+			types[protoreflect.FieldNumber(n)] = sf.Type
+		}
+	}
+	return types
+}
+
+// goMessageType returns the Go type of the messages of mt, or nil if they are dynamic messages.
+func goMessageType(mt protoreflect.MessageType) reflect.Type {
+	m := mt.Zero().Interface()
+	if _, ok := m.(*dynamicpb.Message); ok {
+		return nil
+	}
+	return reflect.TypeOf(m)
+}
+
+// goFieldType returns the Go type protoc-gen-go generates for a field.
+func goFieldType(fd protoreflect.FieldDescriptor) reflect.Type {
+	switch {
+	case fd.IsMap():
+		return reflect.MapOf(goValueType(fd.MapKey()), goValueType(fd.MapValue()))
+	case fd.IsList():
+		return reflect.SliceOf(goValueType(fd))
+	}
+	t := goValueType(fd)
+	if fd.HasPresence() && fd.Message() == nil && fd.Kind() != protoreflect.BytesKind && realOneof(fd) == nil {
+		return reflect.PointerTo(t)
+	}
+	return t
+}
+
+var goScalarTypes = map[protoreflect.Kind]reflect.Type{
+	protoreflect.BoolKind:     reflect.TypeFor[bool](),
+	protoreflect.Int32Kind:    reflect.TypeFor[int32](),
+	protoreflect.Sint32Kind:   reflect.TypeFor[int32](),
+	protoreflect.Sfixed32Kind: reflect.TypeFor[int32](),
+	protoreflect.Int64Kind:    reflect.TypeFor[int64](),
+	protoreflect.Sint64Kind:   reflect.TypeFor[int64](),
+	protoreflect.Sfixed64Kind: reflect.TypeFor[int64](),
+	protoreflect.Uint32Kind:   reflect.TypeFor[uint32](),
+	protoreflect.Fixed32Kind:  reflect.TypeFor[uint32](),
+	protoreflect.Uint64Kind:   reflect.TypeFor[uint64](),
+	protoreflect.Fixed64Kind:  reflect.TypeFor[uint64](),
+	protoreflect.FloatKind:    reflect.TypeFor[float32](),
+	protoreflect.DoubleKind:   reflect.TypeFor[float64](),
+	protoreflect.StringKind:   reflect.TypeFor[string](),
+	protoreflect.BytesKind:    reflect.TypeFor[[]byte](),
+}
+
+// goValueType returns the Go type of a single value of a field, ignoring its cardinality and presence.
+func goValueType(fd protoreflect.FieldDescriptor) reflect.Type {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if mt, err := protoregistry.GlobalTypes.FindMessageByName(fd.Message().FullName()); err == nil {
+			if t := goMessageType(mt); t != nil {
+				return t
+			}
+		}
+		return reflect.TypeFor[proto.Message]()
+	case protoreflect.EnumKind:
+		if et, err := protoregistry.GlobalTypes.FindEnumByName(fd.Enum().FullName()); err == nil {
+			return reflect.TypeOf(et.New(0))
+		}
+		return reflect.TypeFor[protoreflect.EnumNumber]()
+	}
+	return goScalarTypes[fd.Kind()]
+}
+
+// EachGoMessageType creates a sequential iterator over the message types of a registry with the Go struct type backing each.
+//
+// The struct type is the element type of the pointer returned by Zero().Interface(),
+// so [reflect.PointerTo] of it is the type implementing [proto.Message].
+// Dynamic message types, such as those created by [dynamicpb.NewMessageType], are backed by [dynamicpb.Message].
+//
+// Parameters:
+//   - types: A Types implementation providing access to message types
+//
+// Returns:
+//   - An iterator sequence that yields each message type and its Go struct type
+func EachGoMessageType(types Types) iter.Seq2[protoreflect.MessageType, reflect.Type] {
+	return func(yield func(protoreflect.MessageType, reflect.Type) bool) {
+		for mt := range EachMessage(types) {
+			t := reflect.TypeOf(mt.Zero().Interface())
+			if t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if !yield(mt, t) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleEachGoType() {
+	md := (&descriptorpb.EnumValueDescriptorProto{}).ProtoReflect().Descriptor()
+	for fd, t := range protoiter.EachGoType(md) {
+		fmt.Println(fd.Name(), t)
+	}
+	// Output:
+	// name *string
+	// number *int32
+	// options *descriptorpb.EnumValueOptions
+}
+
+func TestEachGoType(t *testing.T) {
+	files := newFiles(`
+		name: "gotype.proto"
+		package: "test"
+		syntax: "proto3"
+		message_type {
+			name: "M"
+			field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_INT64 }
+			field { name: "opt" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING oneof_index: 1 proto3_optional: true }
+			field { name: "tags" number: 3 label: LABEL_REPEATED type: TYPE_STRING }
+			field { name: "self" number: 4 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.M" }
+			field { name: "value" number: 5 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Value" }
+			field { name: "color" number: 6 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".test.Color" }
+			field { name: "data" number: 7 label: LABEL_OPTIONAL type: TYPE_BYTES oneof_index: 0 }
+			oneof_decl { name: "choice" }
+			oneof_decl { name: "_opt" }
+		}
+		enum_type { name: "Color" value { name: "COLOR_UNSPECIFIED" number: 0 } }
+		dependency: "google/protobuf/struct.proto"
+	`)
+	md := results.Must1(files.FindDescriptorByName("test.M")).(protoreflect.MessageDescriptor)
+	want := map[protoreflect.Name]reflect.Type{
+		"id":    reflect.TypeFor[int64](),
+		"opt":   reflect.TypeFor[*string](),
+		"tags":  reflect.TypeFor[[]string](),
+		"self":  reflect.TypeFor[proto.Message](),
+		"value": reflect.TypeFor[*structpb.Value](),
+		"color": reflect.TypeFor[protoreflect.EnumNumber](),
+		"data":  reflect.TypeFor[[]byte](),
+	}
+	for fd, got := range protoiter.EachGoType(md) {
+		if got != want[fd.Name()] {
+			t.Errorf("%s: got %v, want %v", fd.Name(), got, want[fd.Name()])
+		}
+	}
+
+	// The rules for dynamic messages must agree with the generated structs.
+	for _, m := range []proto.Message{&descriptorpb.FileDescriptorSet{}, &structpb.Value{}} {
+		fd := m.ProtoReflect().Descriptor().ParentFile()
+		copied := results.Must1(protodesc.NewFile(protodesc.ToFileDescriptorProto(fd), protoregistry.GlobalFiles))
+		for d := range protoiter.EachQuery(fileSet{copied}, "message") {
+			md := d.(protoreflect.MessageDescriptor)
+			generated := results.Must1(protoregistry.GlobalFiles.FindDescriptorByName(md.FullName())).(protoreflect.MessageDescriptor)
+			dynamic := make(map[protoreflect.Name]reflect.Type)
+			for fd, typ := range protoiter.EachGoType(md) {
+				dynamic[fd.Name()] = typ
+			}
+			for fd, typ := range protoiter.EachGoType(generated) {
+				if dynamic[fd.Name()] != typ {
+					t.Errorf("%v: got %v, want %v", fd.FullName(), dynamic[fd.Name()], typ)
+				}
+			}
+		}
+	}
+}
+
+// fileSet is a Files holding unregistered file descriptors.
+type fileSet []protoreflect.FileDescriptor
+
+func (s fileSet) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	for _, fd := range s {
+		if !f(fd) {
+			return
+		}
+	}
+}
+
+func (s fileSet) RangeFilesByPackage(name protoreflect.FullName, f func(protoreflect.FileDescriptor) bool) {
+	for _, fd := range s {
+		if fd.Package() == name && !f(fd) {
+			return
+		}
+	}
+}
+
+func ExampleEachGoMessageType() {
+	types := new(protoregistry.Types)
+	results.Must(types.RegisterMessage((&structpb.Struct{}).ProtoReflect().Type()))
+	for mt, t := range protoiter.EachGoMessageType(types) {
+		fmt.Println(mt.Descriptor().FullName(), t)
+	}
+	// Output:
+	// google.protobuf.Struct structpb.Struct
+}
+
+func TestEachGoMessageType(t *testing.T) {
+	n := 0
+	for mt, typ := range protoiter.EachGoMessageType(protoregistry.GlobalTypes) {
+		n++
+		if typ.Kind() != reflect.Struct || !reflect.PointerTo(typ).Implements(reflect.TypeFor[proto.Message]()) {
+			t.Errorf("%v: unexpected %v", mt.Descriptor().FullName(), typ)
+		}
+	}
+	if n == 0 {
+		t.Error("no message types")
+	}
+
+	md := results.Must1(newFiles(acmeProto).FindDescriptorByName("acme.store.Blob")).(protoreflect.MessageDescriptor)
+	dynamic := new(protoregistry.Types)
+	results.Must(dynamic.RegisterMessage(dynamicpb.NewMessageType(md)))
+	for mt, typ := range protoiter.EachGoMessageType(dynamic) {
+		if typ != reflect.TypeFor[dynamicpb.Message]() {
+			t.Errorf("%v: got %v", mt.Descriptor().FullName(), typ)
+		}
+	}
+	for range protoiter.EachGoMessageType(protoregistry.GlobalTypes) {
+		break
+	}
+}
@@ -0,0 +1,75 @@
+// Package grpciter provides iterators over the generated gRPC service descriptions
+// and a gRPC server reflection service backed by protoiter registries.
+//
+// It is a separate package, so programs that only import protoiter do not link gRPC.
+package grpciter
+
+import (
+	"iter"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Method describes one method of a [grpc.ServiceDesc], either a unary method or a stream.
+type Method struct {
+	// Name is the method name without the service prefix.
+	Name string
+
+	// FullMethod is the name used for routing, in the form "/package.Service/Method".
+	FullMethod string
+
+	// ClientStreams and ServerStreams report the streaming direction of a stream.
+	// Both are false for a unary method.
+	ClientStreams, ServerStreams bool
+
+	// HasHandler reports whether the description carries a handler for the method.
+	HasHandler bool
+
+	// Descriptor is the method descriptor found in [protoregistry.GlobalFiles],
+	// or nil if the service is not registered there.
+	Descriptor protoreflect.MethodDescriptor
+}
+
+// EachServiceDescMethod creates a sequential iterator over the unary methods and then the streams of a service description.
+//
+// Each method is paired with its descriptor from [protoregistry.GlobalFiles] when the service is registered there,
+// which bridges the generated-code view with the protoreflect view for servers that assemble routing dynamically.
+//
+// Parameters:
+//   - sd: The service description, typically a generated *_ServiceDesc variable
+//
+// Returns:
+//   - An iterator sequence that yields each method
+func EachServiceDescMethod(sd *grpc.ServiceDesc) iter.Seq[Method] {
+	return func(yield func(Method) bool) {
+		var service protoreflect.ServiceDescriptor
+		if d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(sd.ServiceName)); err == nil {
+			service, _ = d.(protoreflect.ServiceDescriptor)
+		}
+		method := func(name string) Method {
+			m := Method{Name: name, FullMethod: "/" + sd.ServiceName + "/" + name}
+			if service != nil {
+				m.Descriptor = service.Methods().ByName(protoreflect.Name(name))
+			}
+			return m
+		}
+		for _, desc := range sd.Methods {
+			m := method(desc.MethodName)
+			m.HasHandler = desc.Handler != nil
+			if !yield(m) {
+				return
+			}
+		}
+		for _, desc := range sd.Streams {
+			m := method(desc.StreamName)
+			m.ClientStreams = desc.ClientStreams
+			m.ServerStreams = desc.ServerStreams
+			m.HasHandler = desc.Handler != nil
+			if !yield(m) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package grpciter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter/grpciter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func ExampleEachServiceDescMethod() {
+	for m := range grpciter.EachServiceDescMethod(&grpc_health_v1.Health_ServiceDesc) {
+		fmt.Println(m.FullMethod, m.ServerStreams, m.HasHandler, m.Descriptor.Output().FullName())
+	}
+	// Output:
+	// /grpc.health.v1.Health/Check false true grpc.health.v1.HealthCheckResponse
+	// /grpc.health.v1.Health/Watch true true grpc.health.v1.HealthCheckResponse
+}
+
+func TestEachServiceDescMethod(t *testing.T) {
+	sd := &grpc.ServiceDesc{
+		ServiceName: "test.Unregistered",
+		Methods:     []grpc.MethodDesc{{MethodName: "Get"}},
+		Streams:     []grpc.StreamDesc{{StreamName: "Sync", ClientStreams: true, ServerStreams: true}},
+	}
+	var got []grpciter.Method
+	for m := range grpciter.EachServiceDescMethod(sd) {
+		got = append(got, m)
+	}
+	want := []grpciter.Method{
+		{Name: "Get", FullMethod: "/test.Unregistered/Get"},
+		{Name: "Sync", FullMethod: "/test.Unregistered/Sync", ClientStreams: true, ServerStreams: true},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+	for range grpciter.EachServiceDescMethod(sd) {
+		break
+	}
+}
@@ -0,0 +1,140 @@
+package grpciter
+
+import (
+	"github.com/goaux/protoiter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// NewReflectionServer returns a gRPC server reflection v1 service that answers from a registry instead of
+// the services registered with a [grpc.Server] and [protoregistry.GlobalFiles].
+//
+// Every service declared in files is listed, whether or not the server implements it,
+// so registries assembled with protoiter pipelines can be served as they are.
+// The files, symbols and extensions of files are indexed once, when the service is created,
+// so requests do not scan the registry; files added to it afterwards are not served.
+//
+// Parameters:
+//   - files: A Files implementation providing the file descriptors to serve
+//
+// Returns:
+//   - The reflection service, to be registered with [grpc_reflection_v1.RegisterServerReflectionServer]
+func NewReflectionServer(files protoiter.Files) grpc_reflection_v1.ServerReflectionServer {
+	r := newResolver(files)
+	return reflection.NewServerV1(reflection.ServerOptions{
+		Services:           r,
+		DescriptorResolver: r,
+		ExtensionResolver:  r,
+	})
+}
+
+// RegisterReflection registers the reflection service of [NewReflectionServer] with a server.
+//
+// Parameters:
+//   - s: The server to register the service with, typically a *grpc.Server
+//   - files: A Files implementation providing the file descriptors to serve
+func RegisterReflection(s grpc.ServiceRegistrar, files protoiter.Files) {
+	grpc_reflection_v1.RegisterServerReflectionServer(s, NewReflectionServer(files))
+}
+
+// resolver serves the services, files, descriptors and extensions of a registry from an index built once.
+type resolver struct {
+	services   map[string]grpc.ServiceInfo
+	files      map[string]protoreflect.FileDescriptor
+	symbols    map[protoreflect.FullName]protoreflect.Descriptor
+	extensions map[protoreflect.FullName][]protoreflect.ExtensionType
+}
+
+// newResolver indexes files. The first declaration of a path or name wins, as in registry order.
+func newResolver(files protoiter.Files) *resolver {
+	r := &resolver{
+		services:   make(map[string]grpc.ServiceInfo),
+		files:      make(map[string]protoreflect.FileDescriptor),
+		symbols:    make(map[protoreflect.FullName]protoreflect.Descriptor),
+		extensions: make(map[protoreflect.FullName][]protoreflect.ExtensionType),
+	}
+	for fd := range protoiter.EachFile(files) {
+		if _, ok := r.files[fd.Path()]; !ok {
+			r.files[fd.Path()] = fd
+		}
+	}
+	for name, d := range protoiter.EachSymbol(files) {
+		if _, ok := r.symbols[name]; ok {
+			continue
+		}
+		r.symbols[name] = d
+		switch d := d.(type) {
+		case protoreflect.ServiceDescriptor:
+			r.services[string(name)] = serviceInfo(d)
+		case protoreflect.ExtensionDescriptor:
+			if d.IsExtension() {
+				extendee := d.ContainingMessage().FullName()
+				r.extensions[extendee] = append(r.extensions[extendee], dynamicpb.NewExtensionType(d))
+			}
+		}
+	}
+	return r
+}
+
+// serviceInfo describes a service as a [reflection.ServiceInfoProvider] lists it.
+func serviceInfo(sd protoreflect.ServiceDescriptor) grpc.ServiceInfo {
+	var methods []grpc.MethodInfo
+	for _, md := range protoiter.Each(sd.Methods()) {
+		methods = append(methods, grpc.MethodInfo{
+			Name:           string(md.Name()),
+			IsClientStream: md.IsStreamingClient(),
+			IsServerStream: md.IsStreamingServer(),
+		})
+	}
+	return grpc.ServiceInfo{Methods: methods, Metadata: sd.ParentFile().Path()}
+}
+
+func (r *resolver) GetServiceInfo() map[string]grpc.ServiceInfo {
+	return r.services
+}
+
+func (r *resolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, ok := r.files[path]; ok {
+		return fd, nil
+	}
+	return nil, protoregistry.NotFound
+}
+
+func (r *resolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, ok := r.symbols[name]; ok {
+		return d, nil
+	}
+	return nil, protoregistry.NotFound
+}
+
+func (r *resolver) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	if xd, ok := r.symbols[field].(protoreflect.ExtensionDescriptor); ok && xd.IsExtension() {
+		for _, xt := range r.extensions[xd.ContainingMessage().FullName()] {
+			if xt.TypeDescriptor().FullName() == field {
+				return xt, nil
+			}
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+func (r *resolver) FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error) {
+	for _, xt := range r.extensions[message] {
+		if xt.TypeDescriptor().Number() == field {
+			return xt, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+func (r *resolver) RangeExtensionsByMessage(message protoreflect.FullName, f func(protoreflect.ExtensionType) bool) {
+	for _, xt := range r.extensions[message] {
+		if !f(xt) {
+			return
+		}
+	}
+}
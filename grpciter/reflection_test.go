@@ -0,0 +1,136 @@
+package grpciter_test
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/protoiter/grpciter"
+	"github.com/goaux/results"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const storeProto = `
+	name: "acme/store.proto"
+	package: "acme.store"
+	message_type {
+		name: "Blob"
+		field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+		extension_range { start: 100 end: 200 }
+	}
+	extension { name: "tag" number: 100 label: LABEL_OPTIONAL type: TYPE_STRING extendee: ".acme.store.Blob" }
+	service {
+		name: "BlobService"
+		method { name: "Get" input_type: ".acme.store.Blob" output_type: ".acme.store.Blob" }
+	}
+`
+
+// reflectionClient serves the reflection service of files in memory and opens a reflection stream to it.
+func reflectionClient(t *testing.T, files protoiter.Files) grpc_reflection_v1.ServerReflection_ServerReflectionInfoClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	grpciter.RegisterReflection(s, files)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	conn := results.Must1(grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	))
+	t.Cleanup(func() { conn.Close() })
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return results.Must1(grpc_reflection_v1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx))
+}
+
+func TestNewReflectionServer(t *testing.T) {
+	fdp := new(descriptorpb.FileDescriptorProto)
+	results.Must(prototext.Unmarshal([]byte(storeProto), fdp))
+	files := new(protoregistry.Files)
+	results.Must(files.RegisterFile(results.Must1(protodesc.NewFile(fdp, files))))
+	counted := &countingFiles{Files: files}
+	stream := reflectionClient(t, counted)
+	indexed := counted.calls
+	ask := func(req *grpc_reflection_v1.ServerReflectionRequest) *grpc_reflection_v1.ServerReflectionResponse {
+		results.Must(stream.Send(req))
+		return results.Must1(stream.Recv())
+	}
+
+	res := ask(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_ListServices{},
+	})
+	var services []string
+	for _, s := range res.GetListServicesResponse().GetService() {
+		services = append(services, s.GetName())
+	}
+	if want := []string{"acme.store.BlobService"}; !slices.Equal(services, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", services, want)
+	}
+
+	for _, req := range []*grpc_reflection_v1.ServerReflectionRequest{
+		{MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "acme.store.BlobService.Get"}},
+		{MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_FileByFilename{FileByFilename: "acme/store.proto"}},
+		{MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_FileContainingExtension{
+			FileContainingExtension: &grpc_reflection_v1.ExtensionRequest{ContainingType: "acme.store.Blob", ExtensionNumber: 100},
+		}},
+	} {
+		res := ask(req)
+		got := res.GetFileDescriptorResponse().GetFileDescriptorProto()
+		if len(got) != 1 {
+			t.Errorf("%v: unexpected response %v", req, res)
+			continue
+		}
+		if !proto.Equal(protoUnmarshal(got[0]), fdp) {
+			t.Errorf("%v: unexpected file", req)
+		}
+	}
+
+	res = ask(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_AllExtensionNumbersOfType{AllExtensionNumbersOfType: "acme.store.Blob"},
+	})
+	if got := res.GetAllExtensionNumbersResponse().GetExtensionNumber(); !slices.Equal(got, []int32{100}) {
+		t.Errorf("unexpected extension numbers %v", got)
+	}
+
+	res = ask(&grpc_reflection_v1.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "acme.store.Missing"},
+	})
+	if res.GetErrorResponse() == nil {
+		t.Errorf("unexpected response %v", res)
+	}
+	if counted.calls != indexed {
+		t.Errorf("requests must be answered from the index, but scanned the registry %d times", counted.calls-indexed)
+	}
+}
+
+// countingFiles counts the scans of a registry.
+type countingFiles struct {
+	*protoregistry.Files
+	calls int
+}
+
+func (c *countingFiles) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	c.calls++
+	c.Files.RangeFiles(f)
+}
+
+func (c *countingFiles) RangeFilesByPackage(name protoreflect.FullName, f func(protoreflect.FileDescriptor) bool) {
+	c.calls++
+	c.Files.RangeFilesByPackage(name, f)
+}
+
+func protoUnmarshal(b []byte) *descriptorpb.FileDescriptorProto {
+	fdp := new(descriptorpb.FileDescriptorProto)
+	results.Must(proto.Unmarshal(b, fdp))
+	return fdp
+}
@@ -0,0 +1,162 @@
+package protoiter
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+	"iter"
+	"math"
+	"slices"
+
+	"github.com/goaux/protoiter/internal/reflectutil"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachFieldHash creates a sequential iterator over the populated fields of a message with a digest of each.
+//
+// A field's digest is computed with a new hash from h over the deterministic encoding,
+// as by proto.MarshalOptions{Deterministic: true}, of a message holding only that field.
+// Equal field values therefore have equal digests within a program, enabling field-level deduplication
+// and change detection; see [CanonicalHash] for digests that do not depend on the protobuf implementation.
+// Fields are yielded in field-number order. Fields that cannot be encoded are skipped.
+//
+// Parameters:
+//   - m: The message whose fields are hashed
+//   - h: The constructor of the hash, e.g. sha256.New
+//
+// Returns:
+//   - An iterator sequence that yields each field descriptor and its digest
+func EachFieldHash(m proto.Message, h func() hash.Hash) iter.Seq2[protoreflect.FieldDescriptor, []byte] {
+	return func(yield func(protoreflect.FieldDescriptor, []byte) bool) {
+		message := m.ProtoReflect()
+		opts := proto.MarshalOptions{Deterministic: true, AllowPartial: true}
+		for fd, v := range EachFieldInOrder(message, numberOrder) {
+			only := message.New()
+			only.Set(fd, v)
+			b, err := opts.Marshal(only.Interface())
+			if err != nil {
+				continue
+			}
+			d := h()
+			d.Write(b)
+			if !yield(fd, d.Sum(nil)) {
+				return
+			}
+		}
+	}
+}
+
+// CanonicalHash computes a content hash of a message that does not depend on marshaling nondeterminism.
+//
+// The message is traversed and each populated field is written to h in a fixed form:
+//   - a message is the varint count of its populated fields, followed by each field in field-number order
+//     (extensions included) as the varint field number and the value
+//   - a list is the varint element count followed by the elements
+//   - a map is the varint entry count followed by the key and value of each entry in key order:
+//     false before true, numbers ascending, strings by UTF-8 bytes
+//   - bool is one byte; signed integers and enums are zigzag varints; unsigned integers are varints;
+//     float and double are their little-endian IEEE 754 bits; string and bytes are length-prefixed
+//
+// Unknown fields are not hashed. The form is stable across protobuf implementations and versions,
+// so equal messages hash equal wherever they are hashed.
+//
+// Parameters:
+//   - m: The message to hash
+//   - h: The hash receiving the canonical form; it is not reset first
+//
+// Returns:
+//   - The sum of h, or the first write error
+func CanonicalHash(m proto.Message, h hash.Hash) ([]byte, error) {
+	w := canonicalWriter{w: h}
+	w.message(m.ProtoReflect())
+	if w.err != nil {
+		return nil, w.err
+	}
+	return h.Sum(nil), nil
+}
+
+// canonicalWriter writes the canonical form of CanonicalHash, keeping the first write error.
+type canonicalWriter struct {
+	w   io.Writer
+	buf []byte
+	err error
+}
+
+func (w *canonicalWriter) write(b []byte) {
+	if w.err == nil {
+		_, w.err = w.w.Write(b)
+	}
+}
+
+func (w *canonicalWriter) varint(v uint64) {
+	w.buf = protowire.AppendVarint(w.buf[:0], v)
+	w.write(w.buf)
+}
+
+func (w *canonicalWriter) message(m protoreflect.Message) {
+	var fields []protoreflect.FieldDescriptor
+	var values []protoreflect.Value
+	for fd, v := range EachFieldInOrder(m, numberOrder) {
+		fields = append(fields, fd)
+		values = append(values, v)
+	}
+	w.varint(uint64(len(fields)))
+	for i, fd := range fields {
+		w.varint(uint64(fd.Number()))
+		v := values[i]
+		switch {
+		case fd.IsMap():
+			mv := v.Map()
+			keys := make([]protoreflect.MapKey, 0, mv.Len())
+			mv.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			slices.SortFunc(keys, reflectutil.CompareMapKeys)
+			w.varint(uint64(len(keys)))
+			for _, k := range keys {
+				w.value(fd.MapKey(), k.Value())
+				w.value(fd.MapValue(), mv.Get(k))
+			}
+		case fd.IsList():
+			list := v.List()
+			w.varint(uint64(list.Len()))
+			for i := range list.Len() {
+				w.value(fd, list.Get(i))
+			}
+		default:
+			w.value(fd, v)
+		}
+	}
+}
+
+// value writes a singular value, list element, map key or map value of the field fd.
+func (w *canonicalWriter) value(fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		w.message(v.Message())
+	case protoreflect.BoolKind:
+		w.varint(protowire.EncodeBool(v.Bool()))
+	case protoreflect.EnumKind:
+		w.varint(protowire.EncodeZigZag(int64(v.Enum())))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		w.varint(protowire.EncodeZigZag(v.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		w.varint(v.Uint())
+	case protoreflect.FloatKind:
+		w.buf = binary.LittleEndian.AppendUint32(w.buf[:0], math.Float32bits(float32(v.Float())))
+		w.write(w.buf)
+	case protoreflect.DoubleKind:
+		w.buf = binary.LittleEndian.AppendUint64(w.buf[:0], math.Float64bits(v.Float()))
+		w.write(w.buf)
+	case protoreflect.StringKind:
+		w.varint(uint64(len(v.String())))
+		w.write([]byte(v.String()))
+	case protoreflect.BytesKind:
+		w.varint(uint64(len(v.Bytes())))
+		w.write(v.Bytes())
+	}
+}
@@ -0,0 +1,95 @@
+package protoiter_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleEachFieldHash() {
+	a := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), Number: proto.Int32(1)}
+	b := &descriptorpb.FieldDescriptorProto{Name: proto.String("id"), Number: proto.Int32(2)}
+	digests := make(map[string][]byte)
+	for fd, sum := range protoiter.EachFieldHash(a, sha256.New) {
+		digests[string(fd.Name())] = sum
+	}
+	for fd, sum := range protoiter.EachFieldHash(b, sha256.New) {
+		fmt.Println(fd.Name(), "changed:", !bytes.Equal(digests[string(fd.Name())], sum))
+	}
+	// Output:
+	// name changed: false
+	// number changed: true
+}
+
+func TestEachFieldHash(t *testing.T) {
+	m := &descriptorpb.DescriptorProto{
+		Name:  proto.String("M"),
+		Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("a")}},
+	}
+	n := 0
+	for fd, sum := range protoiter.EachFieldHash(m, sha256.New) {
+		n++
+		if len(sum) != sha256.Size {
+			t.Errorf("%s: digest size %d", fd.Name(), len(sum))
+		}
+	}
+	if n != 2 {
+		t.Errorf("hashed %d fields, want 2", n)
+	}
+	for range protoiter.EachFieldHash(m, sha256.New) {
+		break
+	}
+}
+
+func ExampleCanonicalHash() {
+	a := results.Must1(structpb.NewStruct(map[string]any{"env": "prod", "n": 1, "tags": []any{"x", "y"}}))
+	b := results.Must1(structpb.NewStruct(map[string]any{"tags": []any{"x", "y"}, "n": 1, "env": "prod"}))
+	ha := results.Must1(protoiter.CanonicalHash(a, sha256.New()))
+	hb := results.Must1(protoiter.CanonicalHash(b, sha256.New()))
+	fmt.Println(bytes.Equal(ha, hb))
+	// Output:
+	// true
+}
+
+func TestCanonicalHash(t *testing.T) {
+	sum := func(m proto.Message) string {
+		return hex.EncodeToString(results.Must1(protoiter.CanonicalHash(m, sha256.New())))
+	}
+	base := map[string]any{"a": 1, "b": "x", "c": []any{true, nil}}
+	if sum(results.Must1(structpb.NewStruct(base))) != sum(results.Must1(structpb.NewStruct(base))) {
+		t.Error("equal messages must hash equal")
+	}
+	seen := map[string]string{}
+	for name, m := range map[string]proto.Message{
+		"empty":     &structpb.Struct{},
+		"base":      results.Must1(structpb.NewStruct(base)),
+		"value":     results.Must1(structpb.NewStruct(map[string]any{"a": 2, "b": "x", "c": []any{true, nil}})),
+		"key":       results.Must1(structpb.NewStruct(map[string]any{"A": 1, "b": "x", "c": []any{true, nil}})),
+		"order":     results.Must1(structpb.NewStruct(map[string]any{"a": 1, "b": "x", "c": []any{nil, true}})),
+		"nested":    &descriptorpb.DescriptorProto{Field: []*descriptorpb.FieldDescriptorProto{{}}},
+		"flattened": &descriptorpb.DescriptorProto{Field: []*descriptorpb.FieldDescriptorProto{{}, {}}},
+	} {
+		if other, ok := seen[sum(m)]; ok {
+			t.Errorf("%s and %s must hash differently", name, other)
+		}
+		seen[sum(m)] = name
+	}
+
+	m := &descriptorpb.FileDescriptorProto{Name: proto.String("a.proto")}
+	b := results.Must1(proto.Marshal(m))
+	m.ProtoReflect().SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 1000, protowire.VarintType), 1))
+	u := new(descriptorpb.FileDescriptorProto)
+	results.Must(proto.Unmarshal(b, u))
+	if sum(m) != sum(u) {
+		t.Error("unknown fields must not be hashed")
+	}
+}
@@ -0,0 +1,48 @@
+package protoiter_test
+
+import (
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// newFiles builds a registry from FileDescriptorProtos written in text format.
+// Files may import each other and the well-known types linked into the test binary,
+// and options may set extensions declared in earlier files.
+// It panics on invalid input, so it can be used from examples as well as tests.
+func newFiles(texts ...string) *protoregistry.Files {
+	files := new(protoregistry.Files)
+	resolver := chainResolver{files, protoregistry.GlobalFiles}
+	for _, text := range texts {
+		fdp := new(descriptorpb.FileDescriptorProto)
+		opts := prototext.UnmarshalOptions{Resolver: dynamicpb.NewTypes(files)}
+		results.Must(opts.Unmarshal([]byte(text), fdp))
+		results.Must(files.RegisterFile(results.Must1(protodesc.NewFile(fdp, resolver))))
+	}
+	return files
+}
+
+// chainResolver resolves descriptors from the first registry that knows them.
+type chainResolver []*protoregistry.Files
+
+func (r chainResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	for _, files := range r {
+		if fd, err := files.FindFileByPath(path); err == nil {
+			return fd, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
+
+func (r chainResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	for _, files := range r {
+		if d, err := files.FindDescriptorByName(name); err == nil {
+			return d, nil
+		}
+	}
+	return nil, protoregistry.NotFound
+}
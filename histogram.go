@@ -0,0 +1,131 @@
+package protoiter
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maxHistogramDepth bounds the nesting of the messages decoded by [Histogram.Add],
+// as the default recursion limit of [google.golang.org/protobuf/proto.UnmarshalOptions] does.
+const maxHistogramDepth = 10000
+
+// HistogramKey identifies a field in a [Histogram].
+type HistogramKey struct {
+	// Message is the full name of the message containing the field.
+	Message protoreflect.FullName
+
+	// Number is the field number.
+	Number protoreflect.FieldNumber
+}
+
+// HistogramEntry holds the statistics of a field in a [Histogram].
+type HistogramEntry struct {
+	// Count is the number of times the field was encoded; every element of a non-packed repeated field counts.
+	Count int
+
+	// Bytes is the total encoded size of the field, including tags.
+	Bytes int
+}
+
+// Histogram accumulates how often each field occurs in wire-format records and how many bytes it takes.
+type Histogram map[HistogramKey]HistogramEntry
+
+// TagHistogram reads varint length-delimited records from r, as written by
+// [google.golang.org/protobuf/encoding/protodelim], and accumulates a [Histogram] of their fields.
+//
+// Fields of nested messages and groups are attributed to the nested message type.
+// The records are decoded with [EachWireField], so unknown fields are counted by number as well.
+// The histogram is keyed by field, so its size depends on the distinct fields seen, not on their numbers.
+// A record larger than [DefaultMaxMessageSize] is rejected with an error wrapping [ErrMessageTooLarge]
+// before anything is allocated for it, and a record nesting messages more than 10000 levels deep is rejected
+// as it would be by [google.golang.org/protobuf/proto.Unmarshal].
+//
+// Parameters:
+//   - r: The stream of length-delimited records
+//   - md: The message descriptor of the records
+//
+// Returns:
+//   - The histogram of all fields, and the first read or decoding error, if any
+func TagHistogram(r io.Reader, md protoreflect.MessageDescriptor) (Histogram, error) {
+	h := make(Histogram)
+	fr := newFrameReader(r, 0)
+	for {
+		offset, record, err := fr.next(DefaultMaxMessageSize)
+		if errors.Is(err, io.EOF) {
+			return h, nil
+		}
+		if err != nil {
+			return h, err
+		}
+		if err := h.Add(md, record); err != nil {
+			return h, fmt.Errorf("protoiter: record at offset %d: %w", offset, err)
+		}
+	}
+}
+
+// Add accumulates the fields of a single wire-format record.
+//
+// A record nesting messages more than 10000 levels deep is rejected.
+//
+// Parameters:
+//   - md: The message descriptor of the record
+//   - record: The wire-format bytes of the record
+//
+// Returns:
+//   - The first decoding error, if any
+func (h Histogram) Add(md protoreflect.MessageDescriptor, record []byte) error {
+	return h.add(md, record, 0)
+}
+
+func (h Histogram) add(md protoreflect.MessageDescriptor, record []byte, depth int) error {
+	if depth > maxHistogramDepth {
+		return fmt.Errorf("protoiter: messages nested more than %d levels deep", maxHistogramDepth)
+	}
+	for field, err := range eachWireField(record, false) {
+		if err != nil {
+			return err
+		}
+		key := HistogramKey{Message: md.FullName(), Number: field.Number}
+		entry := h[key]
+		entry.Count++
+		entry.Bytes += field.Size
+		h[key] = entry
+		fd := md.Fields().ByNumber(field.Number)
+		if fd == nil || fd.Message() == nil {
+			continue
+		}
+		switch field.Type {
+		case protowire.BytesType, protowire.StartGroupType:
+			if err := h.add(fd.Message(), field.Value, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Each creates a sequential iterator over the entries of the histogram,
+// sorted by message name and field number.
+//
+// Returns:
+//   - An iterator sequence that yields each key and its entry
+func (h Histogram) Each() iter.Seq2[HistogramKey, HistogramEntry] {
+	return func(yield func(HistogramKey, HistogramEntry) bool) {
+		keys := slices.SortedFunc(maps.Keys(h), func(x, y HistogramKey) int {
+			return cmp.Or(cmp.Compare(x.Message, y.Message), cmp.Compare(x.Number, y.Number))
+		})
+		for _, key := range keys {
+			if !yield(key, h[key]) {
+				return
+			}
+		}
+	}
+}
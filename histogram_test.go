@@ -0,0 +1,105 @@
+package protoiter_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleTagHistogram() {
+	var buf bytes.Buffer
+	for _, seconds := range []int64{1, 2, 300} {
+		results.Must1(protodelim.MarshalTo(&buf, &durationpb.Duration{Seconds: seconds}))
+	}
+	h := results.Must1(protoiter.TagHistogram(&buf, (*durationpb.Duration)(nil).ProtoReflect().Descriptor()))
+	for key, entry := range h.Each() {
+		fmt.Println(key.Message, key.Number, entry.Count, entry.Bytes)
+	}
+	// Output:
+	// google.protobuf.Duration 1 3 7
+}
+
+func TestTagHistogram(t *testing.T) {
+	var buf bytes.Buffer
+	s := results.Must1(structpb.NewStruct(map[string]any{"a": 1.0, "b": "xy"}))
+	for range 2 {
+		results.Must1(protodelim.MarshalTo(&buf, s))
+	}
+	h, err := protoiter.TagHistogram(&buf, s.ProtoReflect().Descriptor())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := func(message string, number int) protoiter.HistogramEntry {
+		return h[protoiter.HistogramKey{Message: protoreflect.FullName(message), Number: protoreflect.FieldNumber(number)}]
+	}
+	if e := entry("google.protobuf.Struct", 1); e.Count != 4 || e.Bytes != 2*proto.Size(s) {
+		t.Errorf("unexpected Struct.fields entry %+v", e)
+	}
+	if e := entry("google.protobuf.Struct.FieldsEntry", 1); e.Count != 4 || e.Bytes != 4*3 {
+		t.Errorf("unexpected FieldsEntry.key entry %+v", e)
+	}
+	if e := entry("google.protobuf.Value", 2); e.Count != 2 || e.Bytes != 2*9 {
+		t.Errorf("unexpected Value.number_value entry %+v", e)
+	}
+	if e := entry("google.protobuf.Value", 3); e.Count != 2 || e.Bytes != 2*4 {
+		t.Errorf("unexpected Value.string_value entry %+v", e)
+	}
+
+	if _, err := protoiter.TagHistogram(bytes.NewReader([]byte{5, 1}), s.ProtoReflect().Descriptor()); err == nil {
+		t.Error("a truncated record must be an error")
+	}
+
+	// A field number near the maximum is one more key, not a huge allocation.
+	record := protowire.AppendVarint(protowire.AppendTag(nil, protowire.MaxValidNumber, protowire.VarintType), 1)
+	stream := protowire.AppendBytes(nil, record)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	h, err = protoiter.TagHistogram(bytes.NewReader(stream), s.ProtoReflect().Descriptor())
+	runtime.ReadMemStats(&after)
+	if err != nil || len(h) != 1 {
+		t.Errorf("unexpected histogram %v, %v", h, err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("a large field number must not allocate by number, allocated %d bytes", allocated)
+	}
+
+	// A record nesting a recursive message deeper than the limit is an error, not a stack overflow.
+	node := results.Must1(newFiles(`
+		name: "node.proto"
+		package: "test"
+		message_type {
+			name: "Node"
+			field { name: "child" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.Node" }
+		}
+	`).FindDescriptorByName("test.Node")).(protoreflect.MessageDescriptor)
+	const levels = 20000
+	sizes := make([]int, levels) // sizes[i] is the size of the contents of the node i levels from the bottom
+	for i := 1; i < levels; i++ {
+		sizes[i] = protowire.SizeTag(1) + protowire.SizeBytes(sizes[i-1])
+	}
+	var deep []byte
+	for i := levels - 1; i > 0; i-- {
+		deep = protowire.AppendVarint(protowire.AppendTag(deep, 1, protowire.BytesType), uint64(sizes[i-1]))
+	}
+	stream = protowire.AppendBytes([]byte{0}, deep)
+	if _, err := protoiter.TagHistogram(bytes.NewReader(stream), node); err == nil || !strings.Contains(err.Error(), "record at offset 1") {
+		t.Errorf("a deeply nested record must be rejected with its offset, got %v", err)
+	}
+
+	huge := protowire.AppendVarint(nil, 1<<40)
+	if _, err := protoiter.TagHistogram(bytes.NewReader(huge), s.ProtoReflect().Descriptor()); !errors.Is(err, protoiter.ErrMessageTooLarge) {
+		t.Errorf("an oversized record must be rejected before it is read, got %v", err)
+	}
+}
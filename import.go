@@ -0,0 +1,84 @@
+package protoiter
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachFileWithDepth creates a sequential iterator over all files paired with their depth in the import graph.
+//
+// A file without imports has depth 0, and any other file is one deeper than its deepest import,
+// so every file comes after all of its imports. Files are yielded by ascending depth and then by path,
+// which lets layered processing, such as generating leaves first, group files by the key.
+// Imports that are not in files, including placeholders, still count toward the depth.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields the depth and descriptor of each file
+func EachFileWithDepth(files Files) iter.Seq2[int, protoreflect.FileDescriptor] {
+	return func(yield func(int, protoreflect.FileDescriptor) bool) {
+		type entry struct {
+			depth int
+			fd    protoreflect.FileDescriptor
+		}
+		depths := make(map[string]int)
+		var entries []entry
+		files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			entries = append(entries, entry{importDepth(fd, depths), fd})
+			return true
+		})
+		slices.SortFunc(entries, func(a, b entry) int {
+			return cmp.Or(cmp.Compare(a.depth, b.depth), cmp.Compare(a.fd.Path(), b.fd.Path()))
+		})
+		for _, e := range entries {
+			if !yield(e.depth, e.fd) {
+				return
+			}
+		}
+	}
+}
+
+// importDepth returns the depth of fd in the import graph, memoized by path in depths.
+// A file being computed is recorded as -1, so an import cycle does not recurse forever.
+func importDepth(fd protoreflect.FileDescriptor, depths map[string]int) int {
+	if d, ok := depths[fd.Path()]; ok {
+		return max(d, 0)
+	}
+	depths[fd.Path()] = -1
+	d := 0
+	imports := fd.Imports()
+	for i := range imports.Len() {
+		d = max(d, importDepth(imports.Get(i).FileDescriptor, depths)+1)
+	}
+	depths[fd.Path()] = d
+	return d
+}
+
+// EachImportEdge creates a sequential iterator over the import relations of all files in a registry.
+//
+// Each file is paired with each file it imports, in import declaration order, including weak and public imports.
+// Imported files that are not in files, including placeholders, are yielded as well.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields each importing file and the file it imports
+func EachImportEdge(files Files) iter.Seq2[protoreflect.FileDescriptor, protoreflect.FileDescriptor] {
+	return func(yield func(protoreflect.FileDescriptor, protoreflect.FileDescriptor) bool) {
+		files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			imports := fd.Imports()
+			for i := range imports.Len() {
+				if !yield(fd, imports.Get(i).FileDescriptor) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
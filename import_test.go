@@ -0,0 +1,44 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+)
+
+func ExampleEachFileWithDepth() {
+	files := newFiles(envelopeProto, `name: "wrap.proto" dependency: "envelope.proto"`)
+	for depth, fd := range protoiter.EachFileWithDepth(files) {
+		fmt.Println(depth, fd.Path())
+	}
+	// Output:
+	// 1 envelope.proto
+	// 2 wrap.proto
+}
+
+func TestEachFileWithDepth(t *testing.T) {
+	files := newFiles(extProto, envelopeProto, `name: "wrap.proto" dependency: ["envelope.proto", "ext.proto"]`)
+	got := make(map[string]int)
+	for depth, fd := range protoiter.EachFileWithDepth(files) {
+		got[fd.Path()] = depth
+	}
+	want := map[string]int{"ext.proto": 0, "envelope.proto": 1, "wrap.proto": 2}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+	for range protoiter.EachFileWithDepth(files) {
+		break
+	}
+}
+
+func ExampleEachImportEdge() {
+	files := newFiles(envelopeProto, `name: "wrap.proto" dependency: "envelope.proto"`)
+	edges := make(map[string]string)
+	for from, to := range protoiter.EachImportEdge(files) {
+		edges[from.Path()] = to.Path()
+	}
+	fmt.Println(edges)
+	// Output:
+	// map[envelope.proto:google/protobuf/any.proto wrap.proto:envelope.proto]
+}
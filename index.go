@@ -0,0 +1,102 @@
+package protoiter
+
+import (
+	"iter"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// PackageIndex is a snapshot of a file registry indexed by package.
+//
+// Packages are kept sorted by name, the files of a package sorted by path,
+// and the top-level descriptors of a package in file order and then declaration order.
+// Repeated iteration is therefore stable and does not rescan the registry.
+// It implements [Files], iterating over the files in the same order.
+//
+// A PackageIndex is immutable after construction and safe for concurrent use.
+type PackageIndex struct {
+	packages    []protoreflect.FullName
+	files       map[protoreflect.FullName][]protoreflect.FileDescriptor
+	descriptors map[protoreflect.FullName][]protoreflect.Descriptor
+}
+
+// IndexByPackage materializes files into a [PackageIndex].
+//
+// Later changes to files are not reflected in the index.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - The index of the files by package
+func IndexByPackage(files Files) *PackageIndex {
+	x := &PackageIndex{
+		files:       make(map[protoreflect.FullName][]protoreflect.FileDescriptor),
+		descriptors: make(map[protoreflect.FullName][]protoreflect.Descriptor),
+	}
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if _, ok := x.files[fd.Package()]; !ok {
+			x.packages = append(x.packages, fd.Package())
+		}
+		x.files[fd.Package()] = append(x.files[fd.Package()], fd)
+		return true
+	})
+	slices.Sort(x.packages)
+	for _, name := range x.packages {
+		list := x.files[name]
+		slices.SortStableFunc(list, func(a, b protoreflect.FileDescriptor) int {
+			return strings.Compare(a.Path(), b.Path())
+		})
+		for _, fd := range list {
+			eachChild(fd, func(d protoreflect.Descriptor) bool {
+				x.descriptors[name] = append(x.descriptors[name], d)
+				return true
+			})
+		}
+	}
+	return x
+}
+
+// NumPackages reports the number of packages in the index.
+func (x *PackageIndex) NumPackages() int {
+	return len(x.packages)
+}
+
+// EachPackage creates a sequential iterator over the package names in sorted order.
+// Files without a package statement are indexed under the empty name, which sorts first.
+func (x *PackageIndex) EachPackage() iter.Seq[protoreflect.FullName] {
+	return slices.Values(x.packages)
+}
+
+// EachFile creates a sequential iterator over the files of a package, sorted by path.
+func (x *PackageIndex) EachFile(name protoreflect.FullName) iter.Seq[protoreflect.FileDescriptor] {
+	return slices.Values(x.files[name])
+}
+
+// EachDescriptor creates a sequential iterator over the top-level messages, enums, extensions and services of a package.
+// They are yielded file by file, and within a file as messages, enums, extensions and then services in declaration order.
+func (x *PackageIndex) EachDescriptor(name protoreflect.FullName) iter.Seq[protoreflect.Descriptor] {
+	return slices.Values(x.descriptors[name])
+}
+
+// RangeFiles iterates over all indexed files, package by package, while f returns true.
+func (x *PackageIndex) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	for _, name := range x.packages {
+		for _, fd := range x.files[name] {
+			if !f(fd) {
+				return
+			}
+		}
+	}
+}
+
+// RangeFilesByPackage iterates over the indexed files in a given proto package while f returns true.
+func (x *PackageIndex) RangeFilesByPackage(name protoreflect.FullName, f func(protoreflect.FileDescriptor) bool) {
+	for _, fd := range x.files[name] {
+		if !f(fd) {
+			return
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func ExampleIndexByPackage() {
+	index := protoiter.IndexByPackage(newFiles(acmeProto, extProto))
+	for pkg := range index.EachPackage() {
+		fmt.Println(pkg)
+		for d := range index.EachDescriptor(pkg) {
+			fmt.Println(" ", d.FullName())
+		}
+	}
+	// Output:
+	// acme.store
+	//   acme.store.Blob
+	//   acme.store.State
+	//   acme.store.BlobService
+	// test
+	//   test.Base
+	//   test.a
+	//   test.b
+}
+
+func TestIndexByPackage(t *testing.T) {
+	index := protoiter.IndexByPackage(newFiles(acmeProto, extProto, envelopeProto))
+	if n := index.NumPackages(); n != 2 {
+		t.Errorf("NumPackages = %d", n)
+	}
+	var paths []string
+	for fd := range index.EachFile("test") {
+		paths = append(paths, fd.Path())
+	}
+	if want := []string{"envelope.proto", "ext.proto"}; !slices.Equal(paths, want) {
+		t.Errorf("got %v want %v", paths, want)
+	}
+	var all []string
+	for fd := range protoiter.EachFile(index) {
+		all = append(all, fd.Path())
+	}
+	if want := []string{"acme/store.proto", "envelope.proto", "ext.proto"}; !slices.Equal(all, want) {
+		t.Errorf("got %v want %v", all, want)
+	}
+	if n := len(slices.Collect(protoiter.EachFileByPackage(index, "missing"))); n != 0 {
+		t.Errorf("unexpected files in a missing package: %d", n)
+	}
+	for range protoiter.EachFileByPackage(index, protoreflect.FullName("test")) {
+		break
+	}
+	for range protoiter.EachFile(index) {
+		break
+	}
+}
@@ -0,0 +1,55 @@
+// Package reflectutil holds the protoreflect helpers shared by protoiter and protoitertest.
+package reflectutil
+
+import (
+	"cmp"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CompareMapKeys orders map keys as deterministic marshaling does:
+// false before true, numbers in ascending order, and strings lexically.
+func CompareMapKeys(x, y protoreflect.MapKey) int {
+	switch x.Interface().(type) {
+	case bool:
+		return cmp.Compare(boolRank(x.Bool()), boolRank(y.Bool()))
+	case int32, int64:
+		return cmp.Compare(x.Int(), y.Int())
+	case uint32, uint64:
+		return cmp.Compare(x.Uint(), y.Uint())
+	}
+	return cmp.Compare(x.String(), y.String())
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Kind returns the kind of a descriptor, such as "message" or "enum_value", or "" if it is of no known kind.
+func Kind(d protoreflect.Descriptor) string {
+	switch d := d.(type) {
+	case protoreflect.FileDescriptor:
+		return "file"
+	case protoreflect.MessageDescriptor:
+		return "message"
+	case protoreflect.FieldDescriptor:
+		if d.IsExtension() {
+			return "extension"
+		}
+		return "field"
+	case protoreflect.OneofDescriptor:
+		return "oneof"
+	case protoreflect.EnumDescriptor:
+		return "enum"
+	case protoreflect.EnumValueDescriptor:
+		return "enum_value"
+	case protoreflect.ServiceDescriptor:
+		return "service"
+	case protoreflect.MethodDescriptor:
+		return "method"
+	}
+	return ""
+}
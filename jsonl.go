@@ -0,0 +1,38 @@
+package protoiter
+
+import (
+	"io"
+	"iter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// WriteJSONL writes a sequence of messages to w as newline-delimited JSON, one message per line.
+//
+// Each message is marshaled with opts, except that Multiline and Indent are ignored so that every message fits on one line.
+// The messages are written as they are yielded, so a whole dataset can be exported without buffering it.
+//
+// Parameters:
+//   - w: The writer receiving the lines
+//   - seq: The messages to write
+//   - opts: The protojson marshaling options, e.g. with UseProtoNames set
+//
+// Returns:
+//   - The first marshaling or write error, if any
+func WriteJSONL(w io.Writer, seq iter.Seq[proto.Message], opts protojson.MarshalOptions) error {
+	opts.Multiline, opts.Indent = false, ""
+	var buf []byte
+	for m := range seq {
+		var err error
+		buf, err = opts.MarshalAppend(buf[:0], m)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, '\n')
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
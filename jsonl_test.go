@@ -0,0 +1,49 @@
+package protoiter_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleWriteJSONL() {
+	seq := slices.Values([]proto.Message{durationpb.New(1500000000), results.Must1(structpb.NewValue("x"))})
+	var buf bytes.Buffer
+	results.Must(protoiter.WriteJSONL(&buf, seq, protojson.MarshalOptions{}))
+	fmt.Print(buf.String())
+	// Output:
+	// "1.500s"
+	// "x"
+}
+
+func TestWriteJSONL(t *testing.T) {
+	s := results.Must1(structpb.NewStruct(map[string]any{"a": 1, "b": []any{"x", "y"}}))
+	seq := slices.Values([]proto.Message{s, s, s})
+	var buf bytes.Buffer
+	results.Must(protoiter.WriteJSONL(&buf, seq, protojson.MarshalOptions{Multiline: true, Indent: "  "}))
+	sc := bufio.NewScanner(&buf)
+	n := 0
+	for sc.Scan() {
+		n++
+		got := new(structpb.Struct)
+		results.Must(protojson.Unmarshal(sc.Bytes(), got))
+		if !proto.Equal(got, s) {
+			t.Errorf("line %d: %s", n, sc.Text())
+		}
+	}
+	if n != 3 {
+		t.Errorf("got %d lines", n)
+	}
+	if err := protoiter.WriteJSONL(failingWriter{}, seq, protojson.MarshalOptions{}); err == nil {
+		t.Error("a write error must be returned")
+	}
+}
@@ -0,0 +1,57 @@
+package protoiter
+
+import (
+	"iter"
+)
+
+// MergeByKey creates a sequential iterator merging sequences that are each sorted by key into one sorted sequence.
+//
+// Each sequence must yield its keys in ascending order according to less, such as sorted registry iterators
+// from several sources; the result is then sorted as well, and each key is yielded once.
+// When equal keys are yielded by several sequences, or several times by one, the first value wins,
+// taking the sequences in argument order. The sequences are consumed lazily, one element ahead,
+// so merging does not buffer them and stops them all when the loop breaks.
+//
+// Parameters:
+//   - less: The order of the keys, reporting whether a is ordered before b
+//   - seqs: The sorted sequences to merge
+//
+// Returns:
+//   - An iterator sequence that yields each distinct key and its first value in key order
+func MergeByKey[K comparable, V any](less func(a, b K) bool, seqs ...iter.Seq2[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		type head struct {
+			next func() (K, V, bool)
+			key  K
+			val  V
+			ok   bool
+		}
+		heads := make([]head, len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull2(seq)
+			defer stop()
+			heads[i].next = next
+			heads[i].key, heads[i].val, heads[i].ok = next()
+		}
+		for {
+			first := -1
+			for i, h := range heads {
+				if h.ok && (first < 0 || less(h.key, heads[first].key)) {
+					first = i
+				}
+			}
+			if first < 0 {
+				return
+			}
+			k, v := heads[first].key, heads[first].val
+			if !yield(k, v) {
+				return
+			}
+			for i := range heads {
+				for heads[i].ok && heads[i].key == k {
+					heads[i].key, heads[i].val, heads[i].ok = heads[i].next()
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// sortedSymbols yields the symbols of files sorted by full name, each with the path of its file.
+func sortedSymbols(files protoiter.Files) iter.Seq2[protoreflect.FullName, string] {
+	return func(yield func(protoreflect.FullName, string) bool) {
+		symbols := make(map[protoreflect.FullName]string)
+		for name, d := range protoiter.EachSymbol(files) {
+			symbols[name] = d.ParentFile().Path()
+		}
+		for _, name := range slices.Sorted(maps.Keys(symbols)) {
+			if !yield(name, symbols[name]) {
+				return
+			}
+		}
+	}
+}
+
+func ExampleMergeByKey() {
+	local := newFiles(`
+		name: "local.proto"
+		package: "acme"
+		message_type { name: "Order" }
+		message_type { name: "User" }
+	`)
+	shared := newFiles(`
+		name: "shared.proto"
+		package: "acme"
+		message_type { name: "Money" }
+		message_type { name: "User" }
+	`)
+	less := func(a, b protoreflect.FullName) bool { return a < b }
+	for name, path := range protoiter.MergeByKey(less, sortedSymbols(local), sortedSymbols(shared)) {
+		fmt.Println(name, path)
+	}
+	// Output:
+	// acme.Money shared.proto
+	// acme.Order local.proto
+	// acme.User local.proto
+}
+
+func TestMergeByKey(t *testing.T) {
+	pairs := func(s string) iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {
+			for i, word := range strings.Fields(s) {
+				var k int
+				fmt.Sscan(word, &k)
+				if !yield(k, fmt.Sprint(word, "/", i)) {
+					return
+				}
+			}
+		}
+	}
+	less := func(a, b int) bool { return a < b }
+	collect := func(seq iter.Seq2[int, string]) []string {
+		var got []string
+		for _, v := range seq {
+			got = append(got, v)
+		}
+		return got
+	}
+
+	got := collect(protoiter.MergeByKey(less, pairs("1 3 3 5"), pairs(""), pairs("2 3 6"), pairs("0 5 7")))
+	want := []string{"0/0", "1/0", "2/0", "3/1", "5/3", "6/2", "7/2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	if got := collect(protoiter.MergeByKey[int, string](less)); len(got) != 0 {
+		t.Errorf("merging nothing must yield nothing, got %v", got)
+	}
+
+	stopped := 0
+	counted := func(seq iter.Seq2[int, string]) iter.Seq2[int, string] {
+		return func(yield func(int, string) bool) {
+			defer func() { stopped++ }()
+			seq(yield)
+		}
+	}
+	for k := range protoiter.MergeByKey(less, counted(pairs("1 2 3")), counted(pairs("2 4"))) {
+		if k == 2 {
+			break
+		}
+	}
+	if stopped != 2 {
+		t.Errorf("every sequence must be stopped when the loop breaks, %d stopped", stopped)
+	}
+}
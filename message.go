@@ -0,0 +1,75 @@
+package protoiter
+
+import (
+	"fmt"
+	"iter"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SplitRepeated creates a sequential iterator over copies of a message that each hold a bounded part of a repeated field.
+//
+// Every yielded message is a deep copy of m whose repeated field holds at most maxElems consecutive elements of the original,
+// while all other fields are copied unchanged. Together the copies hold every element, in order.
+// If the field is empty, a single copy of m is yielded.
+// This is useful to chunk oversized requests under a message size limit.
+//
+// It panics if fd is not a repeated (non-map) field of m or if maxElems is less than 1.
+//
+// Parameters:
+//   - m: The message to split
+//   - fd: The repeated field to split
+//   - maxElems: The maximum number of elements of fd in each yielded message
+//
+// Returns:
+//   - An iterator sequence that yields each part
+func SplitRepeated(m proto.Message, fd protoreflect.FieldDescriptor, maxElems int) iter.Seq[proto.Message] {
+	if !fd.IsList() || fd.ContainingMessage().FullName() != m.ProtoReflect().Descriptor().FullName() {
+		panic(fmt.Sprintf("protoiter: %v is not a repeated field of %v", fd.FullName(), m.ProtoReflect().Descriptor().FullName()))
+	}
+	if maxElems < 1 {
+		panic(fmt.Sprintf("protoiter: invalid maxElems %d", maxElems))
+	}
+	return func(yield func(proto.Message) bool) {
+		base := proto.Clone(m)
+		base.ProtoReflect().Clear(fd)
+		list := m.ProtoReflect().Get(fd).List()
+		if list.Len() == 0 {
+			yield(base)
+			return
+		}
+		for start := 0; start < list.Len(); start += maxElems {
+			part := proto.Clone(base)
+			dst := part.ProtoReflect().Mutable(fd).List()
+			for i := start; i < min(start+maxElems, list.Len()); i++ {
+				v := list.Get(i)
+				if fd.Message() != nil {
+					v = protoreflect.ValueOfMessage(proto.Clone(v.Message().Interface()).ProtoReflect())
+				}
+				dst.Append(v)
+			}
+			if !yield(part) {
+				return
+			}
+		}
+	}
+}
+
+// MergeAll merges every message of a sequence into a destination message, in order, and returns the destination.
+//
+// Each message is merged with [proto.Merge], so later messages override singular fields set by earlier ones
+// and append to repeated fields, which is the usual semantics of layered configuration overlays.
+//
+// Parameters:
+//   - seq: The sequence of messages to merge
+//   - into: The destination message, which must be mutable
+//
+// Returns:
+//   - The destination message
+func MergeAll[M proto.Message](seq iter.Seq[M], into M) M {
+	for m := range seq {
+		proto.Merge(into, m)
+	}
+	return into
+}
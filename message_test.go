@@ -0,0 +1,103 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func ExampleSplitRepeated() {
+	m := &descriptorpb.DescriptorProto{
+		Name: proto.String("M"),
+		Field: []*descriptorpb.FieldDescriptorProto{
+			{Name: proto.String("a")}, {Name: proto.String("b")}, {Name: proto.String("c")},
+		},
+	}
+	fd := m.ProtoReflect().Descriptor().Fields().ByName("field")
+	for part := range protoiter.SplitRepeated(m, fd, 2) {
+		part := part.(*descriptorpb.DescriptorProto)
+		fmt.Println(part.GetName(), len(part.GetField()), part.GetField()[0].GetName())
+	}
+	// Output:
+	// M 2 a
+	// M 1 c
+}
+
+func TestSplitRepeated(t *testing.T) {
+	m := &descriptorpb.SourceCodeInfo_Location{Path: []int32{1, 2, 3, 4, 5}, Span: []int32{9}}
+	fd := m.ProtoReflect().Descriptor().Fields().ByName("path")
+	var parts []*descriptorpb.SourceCodeInfo_Location
+	for part := range protoiter.SplitRepeated(m, fd, 2) {
+		parts = append(parts, part.(*descriptorpb.SourceCodeInfo_Location))
+	}
+	if len(parts) != 3 || len(parts[2].Path) != 1 || parts[2].Path[0] != 5 || parts[1].Span[0] != 9 {
+		t.Errorf("unexpected parts %v", parts)
+	}
+	parts[0].Span[0] = 0
+	if m.Span[0] != 9 {
+		t.Error("parts must not alias the original")
+	}
+
+	n := 0
+	for range protoiter.SplitRepeated(&descriptorpb.SourceCodeInfo_Location{}, fd, 2) {
+		n++
+	}
+	if n != 1 {
+		t.Errorf("an empty field must yield one message, got %d", n)
+	}
+
+	d := &descriptorpb.DescriptorProto{Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("a")}}}
+	for part := range protoiter.SplitRepeated(d, d.ProtoReflect().Descriptor().Fields().ByName("field"), 1) {
+		part.(*descriptorpb.DescriptorProto).Field[0].Name = proto.String("changed")
+	}
+	if d.Field[0].GetName() != "a" {
+		t.Error("message elements must be copied")
+	}
+
+	for _, f := range []func(){
+		func() { protoiter.SplitRepeated(m, fd, 0) },
+		func() {
+			protoiter.SplitRepeated(m, m.ProtoReflect().Descriptor().Fields().ByName("leading_comments"), 1)
+		},
+		func() { protoiter.SplitRepeated(d, fd, 1) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("must panic")
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func ExampleMergeAll() {
+	overlays := []*descriptorpb.FileOptions{
+		{JavaPackage: proto.String("com.example"), GoPackage: proto.String("example.com/a")},
+		{GoPackage: proto.String("example.com/b")},
+	}
+	merged := protoiter.MergeAll(slices.Values(overlays), &descriptorpb.FileOptions{})
+	fmt.Println(merged.GetJavaPackage(), merged.GetGoPackage())
+	// Output:
+	// com.example example.com/b
+}
+
+func TestMergeAll(t *testing.T) {
+	into := &descriptorpb.SourceCodeInfo_Location{Path: []int32{1}}
+	got := protoiter.MergeAll(slices.Values([]*descriptorpb.SourceCodeInfo_Location{
+		{Path: []int32{2}, LeadingComments: proto.String("a")},
+		{Path: []int32{3}, LeadingComments: proto.String("b")},
+	}), into)
+	if got != into {
+		t.Error("must return the destination")
+	}
+	want := &descriptorpb.SourceCodeInfo_Location{Path: []int32{1, 2, 3}, LeadingComments: proto.String("b")}
+	if !proto.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package protoiter
+
+import (
+	"iter"
+	"slices"
+	"sync"
+)
+
+// Index materializes a sequence of pairs into a multimap from each key to its values in sequence order.
+//
+// It builds lookup tables such as extensions by extendee or methods by request type in one call.
+//
+// Parameters:
+//   - seq: The sequence of key and value pairs
+//
+// Returns:
+//   - A map from each key to the values yielded with it
+func Index[K comparable, V any](seq iter.Seq2[K, V]) map[K][]V {
+	index := make(map[K][]V)
+	for k, v := range seq {
+		index[k] = append(index[k], v)
+	}
+	return index
+}
+
+// LazyIndex is a multimap built by [Index] on the first query.
+//
+// Building is deferred so that an index can be declared up front, for example as a package variable over
+// [google.golang.org/protobuf/reflect/protoregistry.GlobalFiles], without paying for it until it is used.
+// The sequence is consumed exactly once, and a LazyIndex is safe for concurrent queries.
+type LazyIndex[K comparable, V any] struct {
+	seq   iter.Seq2[K, V]
+	once  sync.Once
+	index map[K][]V
+}
+
+// NewLazyIndex returns a [LazyIndex] that is built from seq on the first query.
+func NewLazyIndex[K comparable, V any](seq iter.Seq2[K, V]) *LazyIndex[K, V] {
+	return &LazyIndex[K, V]{seq: seq}
+}
+
+func (x *LazyIndex[K, V]) build() map[K][]V {
+	x.once.Do(func() {
+		x.index = Index(x.seq)
+		x.seq = nil
+	})
+	return x.index
+}
+
+// Get returns a copy of the values indexed under k, or nil if there are none.
+func (x *LazyIndex[K, V]) Get(k K) []V {
+	return slices.Clone(x.build()[k])
+}
+
+// Each creates a sequential iterator over the values indexed under k.
+func (x *LazyIndex[K, V]) Each(k K) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range x.build()[k] {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Len reports the number of distinct keys.
+func (x *LazyIndex[K, V]) Len() int {
+	return len(x.build())
+}
@@ -0,0 +1,72 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// extensionsByExtendee pairs each extension in files with the full name of the message it extends.
+func extensionsByExtendee(files protoiter.Files) iter.Seq2[protoreflect.FullName, protoreflect.FullName] {
+	return func(yield func(protoreflect.FullName, protoreflect.FullName) bool) {
+		for fd := range protoiter.EachFile(files) {
+			for _, xd := range protoiter.Each(fd.Extensions()) {
+				if !yield(xd.ContainingMessage().FullName(), xd.FullName()) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func ExampleIndex() {
+	index := protoiter.Index(extensionsByExtendee(newFiles(extProto)))
+	fmt.Println(index["test.Base"])
+	// Output:
+	// [test.a test.b]
+}
+
+func TestLazyIndex(t *testing.T) {
+	built := 0
+	seq := func(yield func(string, int) bool) {
+		built++
+		for i, k := range []string{"a", "b", "a"} {
+			if !yield(k, i) {
+				return
+			}
+		}
+	}
+	index := protoiter.NewLazyIndex(iter.Seq2[string, int](seq))
+	if built != 0 {
+		t.Fatal("the index must not be built before the first query")
+	}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			index.Len()
+		}()
+	}
+	wg.Wait()
+	if got := index.Get("a"); !slices.Equal(got, []int{0, 2}) {
+		t.Errorf("Get(a) = %v", got)
+	}
+	if got := slices.Collect(index.Each("b")); !slices.Equal(got, []int{1}) {
+		t.Errorf("Each(b) = %v", got)
+	}
+	if got := index.Get("missing"); got != nil {
+		t.Errorf("Get(missing) = %v", got)
+	}
+	if index.Len() != 2 || built != 1 {
+		t.Errorf("Len = %d, built %d times", index.Len(), built)
+	}
+	for range index.Each("a") {
+		break
+	}
+}
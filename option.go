@@ -0,0 +1,245 @@
+package protoiter
+
+import (
+	"iter"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// EachMessageWithOption creates a sequential iterator over the messages, including nested ones,
+// whose MessageOptions set a custom option.
+//
+// The option is found whether the options were parsed with the extension known, in which case it is matched by full name,
+// or without it, in which case it is decoded from the unknown fields of the options with xt.
+// This is the query underlying option-driven code generators, e.g. "all messages marked as resources".
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - xt: The extension type of the MessageOptions option
+//
+// Returns:
+//   - An iterator sequence that yields each message descriptor and the option value
+func EachMessageWithOption(files Files, xt protoreflect.ExtensionType) iter.Seq2[protoreflect.MessageDescriptor, protoreflect.Value] {
+	return eachWithOption[protoreflect.MessageDescriptor](files, xt)
+}
+
+// EachServiceWithOption creates a sequential iterator over the services whose ServiceOptions set a custom option.
+//
+// It is the service-level counterpart of [EachMessageWithOption], e.g. for enumerating middleware configuration
+// such as auth scopes or visibility declared as service options.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - xt: The extension type of the ServiceOptions option
+//
+// Returns:
+//   - An iterator sequence that yields each service descriptor and the option value
+func EachServiceWithOption(files Files, xt protoreflect.ExtensionType) iter.Seq2[protoreflect.ServiceDescriptor, protoreflect.Value] {
+	return eachWithOption[protoreflect.ServiceDescriptor](files, xt)
+}
+
+// FieldOption is a field carrying a custom option, as yielded by [EachFieldWithOption].
+type FieldOption struct {
+	// Field is the field whose FieldOptions set the option.
+	Field protoreflect.FieldDescriptor
+
+	// Value is the value of the option.
+	Value protoreflect.Value
+}
+
+// EachFieldWithOption creates a sequential iterator over the fields of all messages whose FieldOptions set a custom option.
+//
+// It is the field-level counterpart of [EachMessageWithOption], e.g. for collecting fields carrying a sensitivity
+// or masking annotation. Each field is keyed by its containing message, like [EachFieldOfKind];
+// extension declarations are not included.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - xt: The extension type of the FieldOptions option
+//
+// Returns:
+//   - An iterator sequence that yields each message descriptor and a field with its option value
+func EachFieldWithOption(files Files, xt protoreflect.ExtensionType) iter.Seq2[protoreflect.MessageDescriptor, FieldOption] {
+	return func(yield func(protoreflect.MessageDescriptor, FieldOption) bool) {
+		for fd, v := range eachWithOption[protoreflect.FieldDescriptor](files, xt) {
+			if fd.IsExtension() {
+				continue
+			}
+			if !yield(fd.ContainingMessage(), FieldOption{fd, v}) {
+				return
+			}
+		}
+	}
+}
+
+// EachEnumValueOption creates a sequential iterator over the values of an enum whose EnumValueOptions set a custom option.
+//
+// Like [EachMessageWithOption], the option is found whether or not the options were parsed with the extension known.
+// It serves code generators that annotate enum values, e.g. with the allowed transitions of a state machine.
+//
+// Parameters:
+//   - ed: The enum descriptor whose values are iterated
+//   - xt: The extension type of the EnumValueOptions option
+//
+// Returns:
+//   - An iterator sequence that yields each enum value descriptor and the option value, in declaration order
+func EachEnumValueOption(ed protoreflect.EnumDescriptor, xt protoreflect.ExtensionType) iter.Seq2[protoreflect.EnumValueDescriptor, protoreflect.Value] {
+	return func(yield func(protoreflect.EnumValueDescriptor, protoreflect.Value) bool) {
+		values := ed.Values()
+		for i := range values.Len() {
+			vd := values.Get(i)
+			if v, ok := optionValue(vd.Options(), xt); ok && !yield(vd, v) {
+				return
+			}
+		}
+	}
+}
+
+func eachWithOption[D protoreflect.Descriptor](files Files, xt protoreflect.ExtensionType) iter.Seq2[D, protoreflect.Value] {
+	return func(yield func(D, protoreflect.Value) bool) {
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			t, ok := d.(D)
+			if !ok {
+				return true
+			}
+			if v, ok := optionValue(d.Options(), xt); ok {
+				return yield(t, v)
+			}
+			return true
+		})
+	}
+}
+
+// optionValue returns the value of the custom option xt in an options message,
+// decoding it from the unknown fields if the options were parsed without the extension.
+func optionValue(options proto.Message, xt protoreflect.ExtensionType) (protoreflect.Value, bool) {
+	xd := xt.TypeDescriptor()
+	if v, ok := extensionByName(options, xd.FullName()); ok {
+		return v, true
+	}
+	if options == nil {
+		return protoreflect.Value{}, false
+	}
+	unknown := options.ProtoReflect().GetUnknown()
+	if len(unknown) == 0 {
+		return protoreflect.Value{}, false
+	}
+	reparsed := options.ProtoReflect().New()
+	opts := proto.UnmarshalOptions{Resolver: extensionResolver{xd.Number(): xt}, AllowPartial: true}
+	if err := opts.Unmarshal(unknown, reparsed.Interface()); err != nil || !reparsed.Has(xd) {
+		return protoreflect.Value{}, false
+	}
+	return reparsed.Get(xd), true
+}
+
+// EachEffectiveOption creates a sequential iterator over the options in effect at a descriptor.
+//
+// Raw [protoreflect.Descriptor.Options] only hold what is written on the descriptor itself,
+// while some options apply to everything declared in a file and features are inherited down the declaration tree.
+// The options are yielded in three groups, each field of an options message identified by its descriptor:
+//   - the options set on d itself, sorted by number
+//   - unless d is a file, the options set on its file, such as java_package or optimize_for, sorted by number
+//   - the features field of the options of d, holding the resolved [descriptorpb.FeatureSet]
+//
+// The features are resolved as editions do: the defaults of the edition of the file, proto2 and proto3 included,
+// are overridden by the features set on the file, then on each enclosing declaration down to d.
+// Features set as extensions, such as Go features, are inherited but have no defaults.
+// Field options of proto2 and proto3 files that editions replace with features, such as packed,
+// are yielded as they are and not translated into features.
+//
+// Parameters:
+//   - d: The descriptor whose options are iterated
+//
+// Returns:
+//   - An iterator sequence that yields each option field and its effective value
+func EachEffectiveOption(d protoreflect.Descriptor) iter.Seq2[protoreflect.FieldDescriptor, protoreflect.Value] {
+	return func(yield func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+		options := d.Options()
+		if options == nil {
+			return
+		}
+		if !yieldOptions(options, yield) {
+			return
+		}
+		if _, ok := d.(protoreflect.FileDescriptor); !ok && d.ParentFile() != nil {
+			if fileOptions := d.ParentFile().Options(); fileOptions != nil && !yieldOptions(fileOptions, yield) {
+				return
+			}
+		}
+		if fd := options.ProtoReflect().Descriptor().Fields().ByName("features"); fd != nil {
+			yield(fd, protoreflect.ValueOfMessage(effectiveFeatures(d).ProtoReflect()))
+		}
+	}
+}
+
+// yieldOptions yields the options set in an options message except its features, reporting whether to continue.
+func yieldOptions(options proto.Message, yield func(protoreflect.FieldDescriptor, protoreflect.Value) bool) bool {
+	for fd, v := range EachFieldSorted(options.ProtoReflect()) {
+		if fd.Name() == "features" && !fd.IsExtension() {
+			continue
+		}
+		if !yield(fd, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// effectiveFeatures returns the features of d resolved from the edition defaults and the features of its ancestors.
+func effectiveFeatures(d protoreflect.Descriptor) *descriptorpb.FeatureSet {
+	var features *descriptorpb.FeatureSet
+	if d.ParentFile() != nil {
+		features = editionDefaults(fileEdition(d.ParentFile()))
+	} else {
+		features = new(descriptorpb.FeatureSet)
+	}
+	var chain []protoreflect.Descriptor
+	for ; d != nil; d = d.Parent() {
+		chain = append(chain, d)
+	}
+	for _, d := range slices.Backward(chain) {
+		options := d.Options()
+		if options == nil {
+			continue
+		}
+		m := options.ProtoReflect()
+		fd := m.Descriptor().Fields().ByName("features")
+		if fd == nil || !m.Has(fd) {
+			continue
+		}
+		// The features may be a dynamic message, so they are merged through the wire format.
+		b, err := proto.Marshal(m.Get(fd).Message().Interface())
+		if err == nil {
+			_ = proto.UnmarshalOptions{Merge: true}.Unmarshal(b, features)
+		}
+	}
+	return features
+}
+
+// editionDefaults returns the features of an edition, read from the edition_defaults of the fields of FeatureSet.
+func editionDefaults(edition descriptorpb.Edition) *descriptorpb.FeatureSet {
+	features := new(descriptorpb.FeatureSet)
+	m := features.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		options, _ := fd.Options().(*descriptorpb.FieldOptions)
+		var value string
+		latest := descriptorpb.Edition_EDITION_UNKNOWN
+		for _, d := range options.GetEditionDefaults() {
+			if d.GetEdition() <= edition && d.GetEdition() >= latest {
+				value, latest = d.GetValue(), d.GetEdition()
+			}
+		}
+		if fd.Enum() == nil || latest == descriptorpb.Edition_EDITION_UNKNOWN {
+			continue
+		}
+		if vd := fd.Enum().Values().ByName(protoreflect.Name(value)); vd != nil {
+			m.Set(fd, protoreflect.ValueOfEnum(vd.Number()))
+		}
+	}
+	return features
+}
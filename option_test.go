@@ -0,0 +1,258 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const optionProto = `
+	name: "option.proto"
+	package: "opt"
+	dependency: "google/protobuf/descriptor.proto"
+	extension { name: "resource" number: 50000 label: LABEL_OPTIONAL type: TYPE_STRING extendee: ".google.protobuf.MessageOptions" }
+	extension { name: "scope" number: 50001 label: LABEL_REPEATED type: TYPE_STRING extendee: ".google.protobuf.ServiceOptions" }
+	extension { name: "sensitive" number: 50002 label: LABEL_OPTIONAL type: TYPE_BOOL extendee: ".google.protobuf.FieldOptions" }
+	extension { name: "next" number: 50003 label: LABEL_REPEATED type: TYPE_STRING extendee: ".google.protobuf.EnumValueOptions" }
+`
+
+const modelProto = `
+	name: "model.proto"
+	package: "model"
+	dependency: "option.proto"
+	message_type {
+		name: "Book"
+		options { [opt.resource]: "books" }
+		field { name: "title" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "owner" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING options { [opt.sensitive]: true } }
+		nested_type { name: "Page" options { [opt.resource]: "pages" } }
+	}
+	message_type {
+		name: "Note"
+		field { name: "text" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING options { [opt.sensitive]: false } }
+	}
+	enum_type {
+		name: "State"
+		value { name: "STATE_UNSPECIFIED" number: 0 }
+		value { name: "STATE_DRAFT" number: 1 options { [opt.next]: ["STATE_PUBLISHED"] } }
+		value { name: "STATE_PUBLISHED" number: 2 options { [opt.next]: ["STATE_DRAFT", "STATE_ARCHIVED"] } }
+		value { name: "STATE_ARCHIVED" number: 3 }
+	}
+	service { name: "Library" options { [opt.scope]: ["read", "write"] } }
+	service { name: "Public" }
+`
+
+// optionType returns a dynamic extension type for the named option declared in optionProto.
+func optionType(name protoreflect.FullName) protoreflect.ExtensionType {
+	xd := results.Must1(newFiles(optionProto).FindDescriptorByName(name)).(protoreflect.ExtensionDescriptor)
+	return dynamicpb.NewExtensionType(xd)
+}
+
+// withUnknownOptions rebuilds the file at path from its wire form without resolving custom options,
+// so they are kept as unknown fields of the options messages.
+func withUnknownOptions(files *protoregistry.Files, path string) *protoregistry.Files {
+	fd := results.Must1(files.FindFileByPath(path))
+	fdp := new(descriptorpb.FileDescriptorProto)
+	results.Must(proto.Unmarshal(results.Must1(proto.Marshal(protodesc.ToFileDescriptorProto(fd))), fdp))
+	rebuilt := new(protoregistry.Files)
+	results.Must(rebuilt.RegisterFile(results.Must1(protodesc.NewFile(fdp, files))))
+	return rebuilt
+}
+
+func ExampleEachMessageWithOption() {
+	files := newFiles(optionProto, modelProto)
+	for md, v := range protoiter.EachMessageWithOption(files, optionType("opt.resource")) {
+		fmt.Println(md.FullName(), v)
+	}
+	// Output:
+	// model.Book books
+	// model.Book.Page pages
+}
+
+func TestEachMessageWithOption(t *testing.T) {
+	files := withUnknownOptions(newFiles(optionProto, modelProto), "model.proto")
+	got := make(map[protoreflect.FullName]string)
+	for md, v := range protoiter.EachMessageWithOption(files, optionType("opt.resource")) {
+		got[md.FullName()] = v.String()
+	}
+	if fmt.Sprint(got) != "map[model.Book:books model.Book.Page:pages]" {
+		t.Errorf("unexpected messages %v", got)
+	}
+	for range protoiter.EachMessageWithOption(files, optionType("opt.resource")) {
+		break
+	}
+}
+
+func ExampleEachServiceWithOption() {
+	files := newFiles(optionProto, modelProto)
+	for sd, v := range protoiter.EachServiceWithOption(files, optionType("opt.scope")) {
+		fmt.Println(sd.FullName(), v.List().Len())
+	}
+	// Output:
+	// model.Library 2
+}
+
+func ExampleEachFieldWithOption() {
+	files := newFiles(optionProto, modelProto)
+	for md, fo := range protoiter.EachFieldWithOption(files, optionType("opt.sensitive")) {
+		fmt.Println(md.Name(), fo.Field.Name(), fo.Value)
+	}
+	// Output:
+	// Book owner true
+	// Note text false
+}
+
+func TestEachFieldWithOption(t *testing.T) {
+	files := withUnknownOptions(newFiles(optionProto, modelProto), "model.proto")
+	n := 0
+	for md, fo := range protoiter.EachFieldWithOption(files, optionType("opt.sensitive")) {
+		n++
+		if fo.Field.ContainingMessage() != md {
+			t.Errorf("%v is not in %v", fo.Field.FullName(), md.FullName())
+		}
+	}
+	if n != 2 {
+		t.Errorf("found %d fields, want 2", n)
+	}
+	for range protoiter.EachFieldWithOption(files, optionType("opt.sensitive")) {
+		break
+	}
+}
+
+func ExampleEachEnumValueOption() {
+	files := newFiles(optionProto, modelProto)
+	ed := results.Must1(files.FindDescriptorByName("model.State")).(protoreflect.EnumDescriptor)
+	for vd, v := range protoiter.EachEnumValueOption(ed, optionType("opt.next")) {
+		list := v.List()
+		for i := range list.Len() {
+			fmt.Println(vd.Name(), "->", list.Get(i))
+		}
+	}
+	// Output:
+	// STATE_DRAFT -> STATE_PUBLISHED
+	// STATE_PUBLISHED -> STATE_DRAFT
+	// STATE_PUBLISHED -> STATE_ARCHIVED
+}
+
+func TestEachEnumValueOption(t *testing.T) {
+	files := withUnknownOptions(newFiles(optionProto, modelProto), "model.proto")
+	ed := results.Must1(files.FindDescriptorByName("model.State")).(protoreflect.EnumDescriptor)
+	got := make(map[protoreflect.Name]int)
+	for vd, v := range protoiter.EachEnumValueOption(ed, optionType("opt.next")) {
+		got[vd.Name()] = v.List().Len()
+	}
+	if fmt.Sprint(got) != "map[STATE_DRAFT:1 STATE_PUBLISHED:2]" {
+		t.Errorf("unexpected values %v", got)
+	}
+	for range protoiter.EachEnumValueOption(ed, optionType("opt.next")) {
+		break
+	}
+}
+
+func ExampleEachEffectiveOption() {
+	files := newFiles(`
+		name: "acme/order.proto"
+		package: "acme"
+		syntax: "editions"
+		edition: EDITION_2023
+		options { java_package: "com.acme" features { field_presence: IMPLICIT } }
+		message_type {
+			name: "Order"
+			field {
+				name: "id" number: 1 type: TYPE_STRING
+				options { deprecated: true features { field_presence: EXPLICIT } }
+			}
+		}
+	`)
+	fd := results.Must1(files.FindDescriptorByName("acme.Order.id"))
+	for option, v := range protoiter.EachEffectiveOption(fd) {
+		value := v.Interface()
+		if m, ok := value.(protoreflect.Message); ok {
+			value = m.Interface()
+		}
+		fmt.Printf("%s: %v\n", option.FullName(), strings.ReplaceAll(fmt.Sprint(value), "  ", " "))
+	}
+	// Output:
+	// google.protobuf.FieldOptions.deprecated: true
+	// google.protobuf.FileOptions.java_package: com.acme
+	// google.protobuf.FieldOptions.features: field_presence:EXPLICIT enum_type:OPEN repeated_field_encoding:PACKED utf8_validation:VERIFY message_encoding:LENGTH_PREFIXED json_format:ALLOW
+}
+
+func TestEachEffectiveOption(t *testing.T) {
+	features := func(d protoreflect.Descriptor) *descriptorpb.FeatureSet {
+		var got *descriptorpb.FeatureSet
+		for fd, v := range protoiter.EachEffectiveOption(d) {
+			if fd.Name() == "features" {
+				got = v.Message().Interface().(*descriptorpb.FeatureSet)
+			}
+		}
+		return got
+	}
+
+	proto2 := newFiles(`
+		name: "legacy.proto"
+		package: "legacy"
+		options { optimize_for: CODE_SIZE }
+		enum_type { name: "E" value { name: "E_ZERO" number: 0 } }
+	`)
+	ed := results.Must1(proto2.FindDescriptorByName("legacy.E"))
+	want := &descriptorpb.FeatureSet{
+		FieldPresence:         descriptorpb.FeatureSet_EXPLICIT.Enum(),
+		EnumType:              descriptorpb.FeatureSet_CLOSED.Enum(),
+		RepeatedFieldEncoding: descriptorpb.FeatureSet_EXPANDED.Enum(),
+		Utf8Validation:        descriptorpb.FeatureSet_NONE.Enum(),
+		MessageEncoding:       descriptorpb.FeatureSet_LENGTH_PREFIXED.Enum(),
+		JsonFormat:            descriptorpb.FeatureSet_LEGACY_BEST_EFFORT.Enum(),
+	}
+	if got := features(ed); !proto.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	var names []protoreflect.FullName
+	for fd := range protoiter.EachEffectiveOption(ed) {
+		names = append(names, fd.FullName())
+	}
+	if want := []protoreflect.FullName{"google.protobuf.FileOptions.optimize_for", "google.protobuf.EnumOptions.features"}; !slices.Equal(names, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", names, want)
+	}
+
+	editions := newFiles(`
+		name: "edition.proto"
+		package: "ed"
+		syntax: "editions"
+		edition: EDITION_2023
+		options { features { enum_type: CLOSED } }
+		message_type {
+			name: "Outer"
+			options { features { utf8_validation: NONE } }
+			nested_type { name: "Inner" field { name: "s" number: 1 type: TYPE_STRING } }
+		}
+	`)
+	fd := results.Must1(editions.FindDescriptorByName("ed.Outer.Inner.s"))
+	got := features(fd)
+	if got.GetEnumType() != descriptorpb.FeatureSet_CLOSED || got.GetUtf8Validation() != descriptorpb.FeatureSet_NONE ||
+		got.GetFieldPresence() != descriptorpb.FeatureSet_EXPLICIT {
+		t.Errorf("the features must be inherited from the file and the enclosing messages, got %v", got)
+	}
+	file := editions.FindFileByPath
+	names = nil
+	for fd := range protoiter.EachEffectiveOption(results.Must1(file("edition.proto"))) {
+		names = append(names, fd.FullName())
+	}
+	if want := []protoreflect.FullName{"google.protobuf.FileOptions.features"}; !slices.Equal(names, want) {
+		t.Errorf("the options of a file must be yielded once, got %v", names)
+	}
+
+	for range protoiter.EachEffectiveOption(ed) {
+		break
+	}
+}
@@ -0,0 +1,210 @@
+package protoiter
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/goaux/protoiter/internal/reflectutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldOrder specifies the order in which populated fields are visited.
+// It reports whether field x is ordered before field y.
+type FieldOrder func(x, y protoreflect.FieldDescriptor) bool
+
+// JSONOrder is the order in which [google.golang.org/protobuf/encoding/protojson] emits fields:
+// non-extension fields in declaration order, followed by extension fields sorted by full name.
+var JSONOrder FieldOrder = indexNameOrder
+
+// TextOrder is the order in which [google.golang.org/protobuf/encoding/prototext] emits fields.
+// It is the same as [JSONOrder]: non-extension fields in declaration order (not field-number order),
+// followed by extension fields sorted by full name.
+// prototext emits unknown fields after all of them; they are not visited by [protoreflect.Message.Range]
+// and are available from [protoreflect.Message.GetUnknown].
+var TextOrder FieldOrder = indexNameOrder
+
+// MarshalOrder is the order in which [google.golang.org/protobuf/proto] marshals fields:
+// extension fields sorted by number, then fields outside a oneof sorted by number,
+// then fields of a oneof, grouped by the declaration order of their oneofs.
+// Without real oneofs it is plain field-number order.
+var MarshalOrder FieldOrder = marshalOrder
+
+func marshalOrder(x, y protoreflect.FieldDescriptor) bool {
+	if x.IsExtension() != y.IsExtension() {
+		return x.IsExtension()
+	}
+	ox, oy := realOneof(x), realOneof(y)
+	if (ox != nil) != (oy != nil) {
+		return ox == nil
+	}
+	if ox != nil && ox != oy {
+		return ox.Index() < oy.Index()
+	}
+	return x.Number() < y.Number()
+}
+
+// realOneof returns the oneof containing a field, or nil if there is none or it is synthetic.
+func realOneof(fd protoreflect.FieldDescriptor) protoreflect.OneofDescriptor {
+	if od := fd.ContainingOneof(); od != nil && !od.IsSynthetic() {
+		return od
+	}
+	return nil
+}
+
+func indexNameOrder(x, y protoreflect.FieldDescriptor) bool {
+	if x.IsExtension() != y.IsExtension() {
+		return !x.IsExtension()
+	}
+	if x.IsExtension() {
+		return x.FullName() < y.FullName()
+	}
+	return x.Index() < y.Index()
+}
+
+// EachFieldInOrder creates a sequential iterator over the populated fields of a message in a specific order.
+//
+// The populated fields are collected with [protoreflect.Message.Range] and sorted before the first one is yielded.
+// Values are read when they are yielded, so, unlike [EachField], any field may be mutated during iteration;
+// a field cleared before its turn is skipped.
+//
+// Parameters:
+//   - message: The protocol buffer message to iterate over
+//   - order: The order of the fields, e.g. [JSONOrder]
+//
+// Returns:
+//   - An iterator sequence that yields each field descriptor and its corresponding value
+func EachFieldInOrder(message protoreflect.Message, order FieldOrder) iter.Seq2[protoreflect.FieldDescriptor, protoreflect.Value] {
+	return func(yield func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+		fields := populatedFields(message)
+		slices.SortStableFunc(fields, func(x, y protoreflect.FieldDescriptor) int {
+			switch {
+			case order(x, y):
+				return -1
+			case order(y, x):
+				return 1
+			}
+			return 0
+		})
+		yieldFields(message, fields, yield)
+	}
+}
+
+// EachFieldSorted creates a sequential iterator over the populated fields of a message sorted by field number.
+//
+// Unlike [EachField], whose order is undefined, the order is stable across runs and implementations.
+// It is the order of deterministic marshaling for messages without real oneofs;
+// [EachFieldCanonical] follows marshaling exactly.
+// A message that cannot have extensions and declares its fields in ascending number order,
+// as most do, is iterated through its descriptor without buffering; otherwise the populated fields
+// are collected and sorted first, as by [EachFieldInOrder].
+// Either way, values are read when they are yielded, and a field cleared before its turn is skipped.
+//
+// Parameters:
+//   - message: The protocol buffer message to iterate over
+//
+// Returns:
+//   - An iterator sequence that yields each field descriptor and its corresponding value
+func EachFieldSorted(message protoreflect.Message) iter.Seq2[protoreflect.FieldDescriptor, protoreflect.Value] {
+	return func(yield func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+		md := message.Descriptor()
+		if md.ExtensionRanges().Len() > 0 || !declaredInNumberOrder(md.Fields()) {
+			EachFieldInOrder(message, numberOrder)(yield)
+			return
+		}
+		fields := md.Fields()
+		for i := range fields.Len() {
+			fd := fields.Get(i)
+			if message.Has(fd) && !yield(fd, message.Get(fd)) {
+				return
+			}
+		}
+	}
+}
+
+// declaredInNumberOrder reports whether fields are declared in ascending number order.
+func declaredInNumberOrder(fields protoreflect.FieldDescriptors) bool {
+	for i := 1; i < fields.Len(); i++ {
+		if fields.Get(i-1).Number() > fields.Get(i).Number() {
+			return false
+		}
+	}
+	return true
+}
+
+// EachFieldCanonical creates a sequential iterator over the populated fields of a message
+// in the order in which proto.MarshalOptions{Deterministic: true} encodes them.
+//
+// Fields are yielded in [MarshalOrder], and the value of a map field ranges over its entries sorted by key
+// as deterministic marshaling does: false before true, numbers in ascending order, and strings lexically.
+// An encoder or signer that emits each yielded field in turn, and each map entry in Range order,
+// reproduces the deterministic encoding byte-for-byte, except for unknown fields, which are not visited.
+//
+// The value of a map field is a read-only view of the map: its Set, Clear and Mutable methods panic,
+// and it cannot be set on a message. To copy or modify the map, use the value returned by m.Get(fd) instead.
+//
+// Parameters:
+//   - m: The protocol buffer message to iterate over
+//
+// Returns:
+//   - An iterator sequence that yields each field descriptor and its corresponding value
+func EachFieldCanonical(m protoreflect.Message) iter.Seq2[protoreflect.FieldDescriptor, protoreflect.Value] {
+	return func(yield func(protoreflect.FieldDescriptor, protoreflect.Value) bool) {
+		for fd, v := range EachFieldInOrder(m, MarshalOrder) {
+			if fd.IsMap() {
+				v = protoreflect.ValueOfMap(sortedMap{v.Map()})
+			}
+			if !yield(fd, v) {
+				return
+			}
+		}
+	}
+}
+
+// sortedMap is a read-only view of a map whose Range visits the entries sorted by key.
+type sortedMap struct {
+	protoreflect.Map
+}
+
+func (sortedMap) Clear(protoreflect.MapKey) {
+	panic("protoiter: the map yielded by EachFieldCanonical is read-only")
+}
+
+func (sortedMap) Set(protoreflect.MapKey, protoreflect.Value) {
+	panic("protoiter: the map yielded by EachFieldCanonical is read-only")
+}
+
+func (sortedMap) Mutable(protoreflect.MapKey) protoreflect.Value {
+	panic("protoiter: the map yielded by EachFieldCanonical is read-only")
+}
+
+func (m sortedMap) Range(f func(protoreflect.MapKey, protoreflect.Value) bool) {
+	keys := make([]protoreflect.MapKey, 0, m.Len())
+	m.Map.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	slices.SortFunc(keys, reflectutil.CompareMapKeys)
+	for _, k := range keys {
+		if m.Has(k) && !f(k, m.Get(k)) {
+			return
+		}
+	}
+}
+
+// EachMapSorted creates a sequential iterator over the entries of a map value in key order.
+//
+// Keys are ordered as deterministic marshaling orders them: false before true, numbers in ascending order,
+// and strings lexically by their UTF-8 bytes, so output built from the entries is reproducible across runs,
+// e.g. for golden tests. The keys are collected and sorted before the first entry is yielded,
+// and values are read when they are yielded, so an entry deleted before its turn is skipped.
+//
+// Parameters:
+//   - m: The map to iterate over
+//
+// Returns:
+//   - An iterator sequence that yields each map key and its corresponding value
+func EachMapSorted(m protoreflect.Map) iter.Seq2[protoreflect.MapKey, protoreflect.Value] {
+	return func(yield func(protoreflect.MapKey, protoreflect.Value) bool) {
+		sortedMap{m}.Range(yield)
+	}
+}
@@ -0,0 +1,352 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleEachFieldInOrder() {
+	message := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("id"),
+		Number:   proto.Int32(1),
+		JsonName: proto.String("id"),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+	}
+	for field := range protoiter.EachFieldInOrder(message.ProtoReflect(), protoiter.JSONOrder) {
+		fmt.Println(field.Number(), field.JSONName())
+	}
+	// Output:
+	// 1 name
+	// 3 number
+	// 5 type
+	// 10 jsonName
+}
+
+func TestJSONOrder(t *testing.T) {
+	files := newFiles(`
+		name: "order.proto"
+		package: "test"
+		message_type {
+			name: "M"
+			field { name: "c" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 }
+			field { name: "a" number: 3 label: LABEL_OPTIONAL type: TYPE_INT32 }
+			field { name: "b" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 }
+			extension_range { start: 100 end: 200 }
+		}
+		extension { name: "z" number: 100 label: LABEL_OPTIONAL type: TYPE_INT32 extendee: ".test.M" }
+		extension { name: "y" number: 101 label: LABEL_OPTIONAL type: TYPE_INT32 extendee: ".test.M" }
+	`)
+	md := results.Must1(files.FindDescriptorByName("test.M")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	for _, name := range []protoreflect.FullName{"test.z", "test.y"} {
+		xd := results.Must1(files.FindDescriptorByName(name)).(protoreflect.ExtensionDescriptor)
+		m.Set(dynamicpb.NewExtensionType(xd).TypeDescriptor(), protoreflect.ValueOfInt32(9))
+	}
+	for i := range md.Fields().Len() {
+		m.Set(md.Fields().Get(i), protoreflect.ValueOfInt32(int32(i)))
+	}
+
+	var got []protoreflect.FullName
+	for field := range protoiter.EachFieldInOrder(m, protoiter.JSONOrder) {
+		got = append(got, field.FullName())
+	}
+	want := []protoreflect.FullName{"test.M.c", "test.M.a", "test.M.b", "test.y", "test.z"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	// The names appear in the protojson output in the same order.
+	b := string(results.Must1(protojson.Marshal(m)))
+	last := -1
+	for _, name := range []string{`"c"`, `"a"`, `"b"`, `"[test.y]"`, `"[test.z]"`} {
+		i := strings.Index(b, name)
+		if i <= last {
+			t.Errorf("unexpected protojson order %s", b)
+		}
+		last = i
+	}
+
+	for field := range protoiter.EachFieldInOrder(m, protoiter.JSONOrder) {
+		m.Clear(md.Fields().ByName("b"))
+		if field.Name() == "b" {
+			t.Error("a field cleared during iteration must be skipped")
+		}
+	}
+}
+
+func TestTextOrder(t *testing.T) {
+	files := newFiles(`
+		name: "order.proto"
+		package: "test"
+		message_type {
+			name: "M"
+			field { name: "c" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 }
+			field { name: "a" number: 3 label: LABEL_OPTIONAL type: TYPE_INT32 }
+			field { name: "b" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 }
+		}
+	`)
+	md := results.Must1(files.FindDescriptorByName("test.M")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	for i := range md.Fields().Len() {
+		m.Set(md.Fields().Get(i), protoreflect.ValueOfInt32(int32(i+1)))
+	}
+	m.SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 9, protowire.VarintType), 1))
+
+	var got []string
+	for field, value := range protoiter.EachFieldInOrder(m, protoiter.TextOrder) {
+		got = append(got, fmt.Sprintf("%s:%v", field.TextName(), value))
+	}
+	got = append(got, "9:1")
+	text := strings.Join(strings.Fields(prototext.MarshalOptions{EmitUnknown: true}.Format(m)), "")
+	if want := strings.Join(got, ""); text != want {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", want, text)
+	}
+}
+
+func ExampleEachFieldSorted() {
+	message := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("id"),
+		Number:   proto.Int32(1),
+		Extendee: proto.String(".acme.Base"),
+		TypeName: proto.String(".acme.Id"),
+	}
+	for field := range protoiter.EachFieldSorted(message.ProtoReflect()) {
+		fmt.Println(field.Number(), field.Name())
+	}
+	// Output:
+	// 1 name
+	// 2 extendee
+	// 3 number
+	// 6 type_name
+}
+
+func TestEachFieldSorted(t *testing.T) {
+	files := newFiles(`
+		name: "sorted.proto"
+		package: "test"
+		message_type {
+			name: "Unordered"
+			field { name: "c" number: 3 label: LABEL_OPTIONAL type: TYPE_INT32 }
+			field { name: "a" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 oneof_index: 0 }
+			field { name: "b" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 }
+			oneof_decl { name: "o" }
+			extension_range { start: 100 end: 200 }
+		}
+		message_type {
+			name: "Ordered"
+			field { name: "a" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 }
+			field { name: "b" number: 2 label: LABEL_REPEATED type: TYPE_INT32 }
+			field { name: "c" number: 3 label: LABEL_OPTIONAL type: TYPE_STRING }
+		}
+		extension { name: "x" number: 100 label: LABEL_OPTIONAL type: TYPE_INT32 extendee: ".test.Unordered" }
+	`)
+	collect := func(m protoreflect.Message) []string {
+		var got []string
+		for fd, v := range protoiter.EachFieldSorted(m) {
+			if fd.IsList() {
+				v = protoreflect.ValueOfInt64(int64(v.List().Len()))
+			}
+			got = append(got, fmt.Sprintf("%d:%v", fd.Number(), v))
+		}
+		return got
+	}
+
+	md := results.Must1(files.FindDescriptorByName("test.Unordered")).(protoreflect.MessageDescriptor)
+	xd := results.Must1(files.FindDescriptorByName("test.x")).(protoreflect.ExtensionDescriptor)
+	m := dynamicpb.NewMessage(md)
+	results.Must(prototext.Unmarshal([]byte(`c: 3 a: 1 b: 2`), m))
+	m.Set(dynamicpb.NewExtensionType(xd).TypeDescriptor(), protoreflect.ValueOfInt32(100))
+	if got, want := collect(m), []string{"1:1", "2:2", "3:3", "100:100"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	md = results.Must1(files.FindDescriptorByName("test.Ordered")).(protoreflect.MessageDescriptor)
+	m = dynamicpb.NewMessage(md)
+	results.Must(prototext.Unmarshal([]byte(`c: "x" b: [5, 6] a: 0`), m))
+	if got, want := collect(m), []string{"1:0", "2:2", "3:x"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	for fd := range protoiter.EachFieldSorted(m) {
+		m.Clear(md.Fields().ByName("c"))
+		if fd.Name() == "c" {
+			t.Error("a field cleared during iteration must be skipped")
+		}
+	}
+	for fd := range protoiter.EachFieldSorted(m) {
+		if fd.Number() != 1 {
+			t.Errorf("iteration must stop at the first field, got %v", fd.Name())
+		}
+		break
+	}
+}
+
+func ExampleEachFieldCanonical() {
+	m := &descriptorpb.FieldOptions{
+		Deprecated: proto.Bool(true),
+		Packed:     proto.Bool(false),
+		Ctype:      descriptorpb.FieldOptions_CORD.Enum(),
+	}
+	for fd := range protoiter.EachFieldCanonical(m.ProtoReflect()) {
+		fmt.Println(fd.Number(), fd.Name())
+	}
+	// Output:
+	// 1 ctype
+	// 2 packed
+	// 3 deprecated
+}
+
+func TestEachFieldCanonical(t *testing.T) {
+	files := newFiles(`
+		name: "canonical.proto"
+		package: "test"
+		message_type {
+			name: "M"
+			field { name: "b" number: 5 label: LABEL_OPTIONAL type: TYPE_INT32 oneof_index: 0 }
+			field { name: "a" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING oneof_index: 0 }
+			field { name: "z" number: 9 label: LABEL_OPTIONAL type: TYPE_BOOL oneof_index: 1 }
+			field { name: "c" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 }
+			field { name: "m" number: 3 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".test.M.MEntry" }
+			field { name: "r" number: 4 label: LABEL_REPEATED type: TYPE_SINT64 options { packed: true } }
+			field { name: "k" number: 6 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".test.M.KEntry" }
+			nested_type {
+				name: "MEntry"
+				field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+				field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 }
+				options { map_entry: true }
+			}
+			nested_type {
+				name: "KEntry"
+				field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_SINT32 }
+				field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+				options { map_entry: true }
+			}
+			oneof_decl { name: "first" }
+			oneof_decl { name: "second" }
+			extension_range { start: 100 end: 200 }
+		}
+		extension { name: "y" number: 101 label: LABEL_OPTIONAL type: TYPE_INT32 extendee: ".test.M" }
+		extension { name: "x" number: 100 label: LABEL_OPTIONAL type: TYPE_STRING extendee: ".test.M" }
+	`)
+	md := results.Must1(files.FindDescriptorByName("test.M")).(protoreflect.MessageDescriptor)
+	xy := dynamicpb.NewExtensionType(results.Must1(files.FindDescriptorByName("test.y")).(protoreflect.ExtensionDescriptor))
+	xx := dynamicpb.NewExtensionType(results.Must1(files.FindDescriptorByName("test.x")).(protoreflect.ExtensionDescriptor))
+	opts := proto.MarshalOptions{Deterministic: true}
+	n := 0
+	for message := range protoiter.GenerateMessages(md, 1, 50) {
+		m := message.ProtoReflect()
+		if n++; n%2 == 0 {
+			m.Set(xy.TypeDescriptor(), protoreflect.ValueOfInt32(int32(n)))
+			m.Set(xx.TypeDescriptor(), protoreflect.ValueOfString("x"))
+		}
+		// Encode each field, and each map entry, on its own in the yielded order.
+		var got []byte
+		for fd, v := range protoiter.EachFieldCanonical(m) {
+			if !fd.IsMap() {
+				only := m.New()
+				only.Set(fd, v)
+				got = append(got, results.Must1(opts.Marshal(only.Interface()))...)
+				continue
+			}
+			v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				only := m.New()
+				only.Mutable(fd).Map().Set(k, v)
+				got = append(got, results.Must1(opts.Marshal(only.Interface()))...)
+				return true
+			})
+			// The yielded map is read-only; m.Get(fd) can be set on another message.
+			m.New().Set(fd, m.Get(fd))
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Error("setting an entry of the yielded map must panic")
+					}
+				}()
+				v.Map().Set(fd.MapKey().Default().MapKey(), v.Map().NewValue())
+			}()
+		}
+		if want := results.Must1(opts.Marshal(message)); string(got) != string(want) {
+			t.Fatalf("must be equal\ngot\t%x\nwant\t%x", got, want)
+		}
+	}
+}
+
+func ExampleEachMapSorted() {
+	s := results.Must1(structpb.NewStruct(map[string]any{"b": 2, "a": 1, "B": 3}))
+	fields := s.ProtoReflect().Get(s.ProtoReflect().Descriptor().Fields().ByName("fields")).Map()
+	for k, v := range protoiter.EachMapSorted(fields) {
+		fmt.Println(k, v.Message().Interface().(*structpb.Value).AsInterface())
+	}
+	// Output:
+	// B 3
+	// a 1
+	// b 2
+}
+
+func TestEachMapSorted(t *testing.T) {
+	files := newFiles(`
+		name: "maps.proto"
+		package: "test"
+		message_type {
+			name: "M"
+			field { name: "ints" number: 1 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".test.M.IntsEntry" }
+			field { name: "bools" number: 2 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".test.M.BoolsEntry" }
+			nested_type {
+				name: "IntsEntry"
+				field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_SINT64 }
+				field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+				options { map_entry: true }
+			}
+			nested_type {
+				name: "BoolsEntry"
+				field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_BOOL }
+				field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+				options { map_entry: true }
+			}
+		}
+	`)
+	md := results.Must1(files.FindDescriptorByName("test.M")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	results.Must(prototext.Unmarshal([]byte(`
+		ints { key: 10 value: "ten" } ints { key: -3 value: "minus three" } ints { key: 2 value: "two" }
+		bools { key: true value: "yes" } bools { key: false value: "no" }
+	`), m))
+	collect := func(name protoreflect.Name) []string {
+		var got []string
+		for k, v := range protoiter.EachMapSorted(m.Get(md.Fields().ByName(name)).Map()) {
+			got = append(got, fmt.Sprint(k, "=", v))
+		}
+		return got
+	}
+	if got, want := collect("ints"), []string{"-3=minus three", "2=two", "10=ten"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	if got, want := collect("bools"), []string{"false=no", "true=yes"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	ints := m.Mutable(md.Fields().ByName("ints")).Map()
+	n := 0
+	for k := range protoiter.EachMapSorted(ints) {
+		n++
+		if k.Int() == -3 {
+			ints.Clear(protoreflect.ValueOfInt64(10).MapKey())
+		}
+	}
+	if n != 2 {
+		t.Errorf("a deleted entry must be skipped, got %d entries", n)
+	}
+}
@@ -0,0 +1,63 @@
+package protoiter
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// OrderedFiles is a file registry that remembers the order in which files were registered.
+//
+// It implements [Files], so [EachFile] and [EachFileByPackage] iterate over its files in registration order
+// instead of the undefined order of [protoregistry.Files].
+// Lookups are delegated to an underlying [protoregistry.Files], which also enforces its conflict rules.
+//
+// The zero value is an empty registry ready to use.
+// Like protoregistry.Files, it is not safe for concurrent registration and iteration.
+type OrderedFiles struct {
+	files protoregistry.Files
+	order []protoreflect.FileDescriptor
+}
+
+// RegisterFile registers the provided file descriptor.
+//
+// It returns the error of [protoregistry.Files.RegisterFile], and a file that fails to register is not recorded.
+func (r *OrderedFiles) RegisterFile(fd protoreflect.FileDescriptor) error {
+	if err := r.files.RegisterFile(fd); err != nil {
+		return err
+	}
+	r.order = append(r.order, fd)
+	return nil
+}
+
+// FindFileByPath looks up a file by the path.
+func (r *OrderedFiles) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	return r.files.FindFileByPath(path)
+}
+
+// FindDescriptorByName looks up a descriptor by the full name.
+func (r *OrderedFiles) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	return r.files.FindDescriptorByName(name)
+}
+
+// NumFiles reports the number of registered files.
+func (r *OrderedFiles) NumFiles() int {
+	return len(r.order)
+}
+
+// RangeFiles iterates over all registered files in registration order while f returns true.
+func (r *OrderedFiles) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	for _, fd := range r.order {
+		if !f(fd) {
+			return
+		}
+	}
+}
+
+// RangeFilesByPackage iterates over the registered files in a given proto package in registration order while f returns true.
+func (r *OrderedFiles) RangeFilesByPackage(name protoreflect.FullName, f func(protoreflect.FileDescriptor) bool) {
+	for _, fd := range r.order {
+		if fd.Package() == name && !f(fd) {
+			return
+		}
+	}
+}
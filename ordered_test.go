@@ -0,0 +1,51 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/reflect/protodesc"
+)
+
+func ExampleOrderedFiles() {
+	src := newFiles(`name: "c.proto" package: "p"`, `name: "a.proto" package: "q"`, `name: "b.proto" package: "p"`)
+	var files protoiter.OrderedFiles
+	for _, path := range []string{"c.proto", "a.proto", "b.proto"} {
+		results.Must(files.RegisterFile(results.Must1(src.FindFileByPath(path))))
+	}
+	for file := range protoiter.EachFile(&files) {
+		fmt.Println(file.Path())
+	}
+	// Output:
+	// c.proto
+	// a.proto
+	// b.proto
+}
+
+func TestOrderedFiles(t *testing.T) {
+	src := newFiles(`name: "c.proto" package: "p"`, `name: "a.proto" package: "q"`, `name: "b.proto" package: "p"`)
+	var files protoiter.OrderedFiles
+	var _ protodesc.Resolver = &files
+	for _, path := range []string{"c.proto", "a.proto", "b.proto"} {
+		results.Must(files.RegisterFile(results.Must1(src.FindFileByPath(path))))
+	}
+	if err := files.RegisterFile(results.Must1(src.FindFileByPath("a.proto"))); err == nil {
+		t.Error("must reject a duplicate file")
+	}
+	if files.NumFiles() != 3 {
+		t.Errorf("must have 3 files, got %d", files.NumFiles())
+	}
+	var got []string
+	for file := range protoiter.EachFileByPackage(&files, "p") {
+		got = append(got, file.Path())
+	}
+	if want := []string{"c.proto", "b.proto"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	if _, err := files.FindFileByPath("b.proto"); err != nil {
+		t.Error(err)
+	}
+}
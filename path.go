@@ -0,0 +1,84 @@
+package protoiter
+
+import (
+	"iter"
+	"slices"
+
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/reflect/protorange"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// noAnyExpansion makes protorange treat google.protobuf.Any as a regular message.
+var noAnyExpansion = protorange.Options{Stable: true, Resolver: (*protoregistry.Types)(nil)}
+
+// EachPath creates a sequential iterator over the paths of the populated leaves of a message.
+//
+// A leaf is a scalar field value, a scalar list element, a scalar map value, or a populated message without populated fields.
+// Messages, lists and maps are descended into, with fields in ascending field-number order and map entries in key order.
+// google.protobuf.Any messages are not expanded.
+// Each yielded path starts with a [protopath.Root] step and is a new slice that the caller may retain.
+//
+// Parameters:
+//   - message: The protocol buffer message to iterate over
+//
+// Returns:
+//   - An iterator sequence that yields the path of each populated leaf
+func EachPath(message protoreflect.Message) iter.Seq[protopath.Path] {
+	return func(yield func(protopath.Path) bool) {
+		noAnyExpansion.Range(message, func(p protopath.Values) error {
+			if len(p.Path) == 1 || !isLeaf(p.Index(-1).Value) {
+				return nil
+			}
+			if !yield(slices.Clone(p.Path)) {
+				return protorange.Terminate
+			}
+			return nil
+		}, nil)
+	}
+}
+
+// isLeaf reports whether v is a scalar or a message without populated fields.
+func isLeaf(v protoreflect.Value) bool {
+	switch v := v.Interface().(type) {
+	case protoreflect.Message:
+		empty := true
+		v.Range(func(protoreflect.FieldDescriptor, protoreflect.Value) bool {
+			empty = false
+			return false
+		})
+		return empty
+	case protoreflect.List, protoreflect.Map:
+		return false
+	}
+	return true
+}
+
+// WalkValues creates a sequential iterator over every value nested in a message, with the path to each.
+//
+// It is the value-space counterpart of [Walk]: every populated field value is yielded, and lists and maps
+// are yielded themselves before each of their elements and entry values, as are messages before their fields.
+// The traversal is depth-first, with fields in ascending field-number order and map entries in key order,
+// and google.protobuf.Any messages are not expanded. The message itself is not yielded.
+// Each yielded path starts with a [protopath.Root] step and is a new slice that the caller may retain,
+// so a redaction or transform tool can collect the paths to rewrite; the message must not be modified during iteration.
+//
+// Parameters:
+//   - message: The protocol buffer message to walk
+//
+// Returns:
+//   - An iterator sequence that yields the path and value of each nested value
+func WalkValues(message protoreflect.Message) iter.Seq2[protopath.Path, protoreflect.Value] {
+	return func(yield func(protopath.Path, protoreflect.Value) bool) {
+		noAnyExpansion.Range(message, func(p protopath.Values) error {
+			if len(p.Path) == 1 {
+				return nil
+			}
+			if !yield(slices.Clone(p.Path), p.Index(-1).Value) {
+				return protorange.Terminate
+			}
+			return nil
+		}, nil)
+	}
+}
@@ -0,0 +1,149 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleEachPath() {
+	m := &descriptorpb.DescriptorProto{
+		Name:    proto.String("M"),
+		Field:   []*descriptorpb.FieldDescriptorProto{{Name: proto.String("id"), Number: proto.Int32(1)}},
+		Options: &descriptorpb.MessageOptions{},
+	}
+	for path := range protoiter.EachPath(m.ProtoReflect()) {
+		fmt.Println(path)
+	}
+	// Output:
+	// (google.protobuf.DescriptorProto).name
+	// (google.protobuf.DescriptorProto).field[0].name
+	// (google.protobuf.DescriptorProto).field[0].number
+	// (google.protobuf.DescriptorProto).options
+}
+
+func TestEachPath(t *testing.T) {
+	s := results.Must1(structpb.NewStruct(map[string]any{"b": []any{1}, "a": "x"}))
+	var got []string
+	for path := range protoiter.EachPath(s.ProtoReflect()) {
+		if path[0].Kind() != protopath.RootStep {
+			t.Errorf("%v must start with a root step", path)
+		}
+		got = append(got, path.String())
+	}
+	want := []string{
+		`(google.protobuf.Struct).fields["a"].string_value`,
+		`(google.protobuf.Struct).fields["b"].list_value.values[0].number_value`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	a := results.Must1(anypb.New(durationpb.New(1)))
+	got = nil
+	for path := range protoiter.EachPath(a.ProtoReflect()) {
+		got = append(got, path.String())
+	}
+	want = []string{"(google.protobuf.Any).type_url", "(google.protobuf.Any).value"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must not expand Any\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	n := 0
+	for range protoiter.EachPath(s.ProtoReflect()) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("must stop after break, got %d", n)
+	}
+}
+
+func ExampleWalkValues() {
+	m := &descriptorpb.DescriptorProto{
+		Name:  proto.String("M"),
+		Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("id")}},
+	}
+	for path, v := range protoiter.WalkValues(m.ProtoReflect()) {
+		switch v := v.Interface().(type) {
+		case protoreflect.List:
+			fmt.Println(path, "list of", v.Len())
+		case protoreflect.Message:
+			fmt.Println(path, "message")
+		default:
+			fmt.Println(path, v)
+		}
+	}
+	// Output:
+	// (google.protobuf.DescriptorProto).name M
+	// (google.protobuf.DescriptorProto).field list of 1
+	// (google.protobuf.DescriptorProto).field[0] message
+	// (google.protobuf.DescriptorProto).field[0].name id
+}
+
+func TestWalkValues(t *testing.T) {
+	s := results.Must1(structpb.NewStruct(map[string]any{"b": []any{1}, "a": "x"}))
+	var got []string
+	for path, v := range protoiter.WalkValues(s.ProtoReflect()) {
+		if path[0].Kind() != protopath.RootStep {
+			t.Errorf("%v must start with a root step", path)
+		}
+		if !v.IsValid() {
+			t.Errorf("%v must have a valid value", path)
+		}
+		got = append(got, path.String())
+	}
+	want := []string{
+		`(google.protobuf.Struct).fields`,
+		`(google.protobuf.Struct).fields["a"]`,
+		`(google.protobuf.Struct).fields["a"].string_value`,
+		`(google.protobuf.Struct).fields["b"]`,
+		`(google.protobuf.Struct).fields["b"].list_value`,
+		`(google.protobuf.Struct).fields["b"].list_value.values`,
+		`(google.protobuf.Struct).fields["b"].list_value.values[0]`,
+		`(google.protobuf.Struct).fields["b"].list_value.values[0].number_value`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	// The paths can be retained and used after the walk, e.g. to redact strings.
+	var redact []protopath.Path
+	for path, v := range protoiter.WalkValues(s.ProtoReflect()) {
+		if _, ok := v.Interface().(string); ok && path.Index(-1).Kind() == protopath.FieldAccessStep {
+			redact = append(redact, path)
+		}
+	}
+	if len(redact) != 1 || redact[0].String() != `(google.protobuf.Struct).fields["a"].string_value` {
+		t.Errorf("unexpected string paths %v", redact)
+	}
+
+	a := results.Must1(anypb.New(durationpb.New(1)))
+	got = nil
+	for path := range protoiter.WalkValues(a.ProtoReflect()) {
+		got = append(got, path.String())
+	}
+	want = []string{"(google.protobuf.Any).type_url", "(google.protobuf.Any).value"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must not expand Any\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	n := 0
+	for range protoiter.WalkValues(s.ProtoReflect()) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("must stop after break, got %d", n)
+	}
+}
@@ -0,0 +1,65 @@
+package protoitertest
+
+import (
+	"iter"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// AssertElementsMatch reports an error on tb unless got yields the same elements as want, in any order.
+//
+// Elements are compared with equal, and duplicates must occur the same number of times on both sides.
+// On mismatch, the missing and unexpected elements are listed, rendered with [Text].
+//
+// Parameters:
+//   - tb: The test or benchmark reporting a mismatch
+//   - got: The sequence under test
+//   - want: The expected elements
+//   - equal: The function reporting whether two elements are equal
+//
+// Returns:
+//   - Whether the elements match
+func AssertElementsMatch[T any](tb testing.TB, got iter.Seq[T], want []T, equal func(a, b T) bool) bool {
+	tb.Helper()
+	matched := make([]bool, len(want))
+	var extra []string
+next:
+	for v := range got {
+		for i, w := range want {
+			if !matched[i] && equal(v, w) {
+				matched[i] = true
+				continue next
+			}
+		}
+		extra = append(extra, Text(v))
+	}
+	var missing []string
+	for i, ok := range matched {
+		if !ok {
+			missing = append(missing, Text(want[i]))
+		}
+	}
+	if len(missing) == 0 && len(extra) == 0 {
+		return true
+	}
+	var sb strings.Builder
+	sb.WriteString("protoitertest: elements differ")
+	for _, s := range missing {
+		sb.WriteString("\n-" + s)
+	}
+	for _, s := range extra {
+		sb.WriteString("\n+" + s)
+	}
+	tb.Error(sb.String())
+	return false
+}
+
+// AssertMessagesMatch is [AssertElementsMatch] for messages compared with [proto.Equal].
+func AssertMessagesMatch[M proto.Message](tb testing.TB, got iter.Seq[M], want []M) bool {
+	tb.Helper()
+	return AssertElementsMatch(tb, got, want, func(a, b M) bool {
+		return proto.Equal(a, b)
+	})
+}
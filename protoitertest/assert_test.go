@@ -0,0 +1,47 @@
+package protoitertest_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter/protoitertest"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// recorder captures the errors reported to it.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func TestAssertElementsMatch(t *testing.T) {
+	equal := func(a, b int) bool { return a == b }
+	protoitertest.AssertElementsMatch(t, slices.Values([]int{3, 1, 2, 1}), []int{1, 1, 2, 3}, equal)
+
+	r := new(recorder)
+	if protoitertest.AssertElementsMatch(r, slices.Values([]int{1, 2, 2}), []int{1, 1, 2}, equal) {
+		t.Error("a mismatch must return false")
+	}
+	if want := "protoitertest: elements differ\n-1\n+2"; len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("reported %q", r.errors)
+	}
+}
+
+func TestAssertMessagesMatch(t *testing.T) {
+	got := []*durationpb.Duration{durationpb.New(2), durationpb.New(1)}
+	protoitertest.AssertMessagesMatch(t, slices.Values(got), []*durationpb.Duration{durationpb.New(1), durationpb.New(2)})
+
+	r := new(recorder)
+	protoitertest.AssertMessagesMatch(r, slices.Values([]*wrapperspb.StringValue{wrapperspb.String("a")}), nil)
+	if want := `protoitertest: elements differ` + "\n" + `+{value: "a"}`; len(r.errors) != 1 || r.errors[0] != want {
+		t.Errorf("reported %q", r.errors)
+	}
+}
@@ -0,0 +1,219 @@
+// Package protoitertest provides helpers for testing code built on protoiter.
+package protoitertest
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter/internal/reflectutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var update = flag.Bool("protoitertest.update", false, "rewrite golden files instead of comparing against them")
+
+// Golden materializes seq, renders each element with [Text], sorts the lines,
+// and compares the result against the golden file at path.
+//
+// Sorting makes the comparison independent of the iteration order, which is
+// undefined for most registry and message iterators.
+// When the test binary runs with -protoitertest.update, the golden file is
+// rewritten with the current output instead.
+//
+// Parameters:
+//   - tb: The test or benchmark reporting a mismatch
+//   - path: The path of the golden file, typically under testdata
+//   - seq: The sequence to materialize
+func Golden[T any](tb testing.TB, path string, seq iter.Seq[T]) {
+	tb.Helper()
+	var lines []string
+	for v := range seq {
+		lines = append(lines, Text(v))
+	}
+	compare(tb, path, lines)
+}
+
+// Golden2 is like [Golden] but for sequences of pairs.
+// Each pair is rendered as the two texts separated by a tab.
+//
+// Parameters:
+//   - tb: The test or benchmark reporting a mismatch
+//   - path: The path of the golden file, typically under testdata
+//   - seq: The sequence to materialize
+func Golden2[K, V any](tb testing.TB, path string, seq iter.Seq2[K, V]) {
+	tb.Helper()
+	var lines []string
+	for k, v := range seq {
+		lines = append(lines, Text(k)+"\t"+Text(v))
+	}
+	compare(tb, path, lines)
+}
+
+func compare(tb testing.TB, path string, lines []string) {
+	tb.Helper()
+	slices.Sort(lines)
+	got := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		got += "\n"
+	}
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("protoitertest: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			tb.Fatalf("protoitertest: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("protoitertest: %v (run with -protoitertest.update to create it)", err)
+		return
+	}
+	if got != string(want) {
+		tb.Errorf("protoitertest: output differs from %s\n%s", path, Diff(string(want), got))
+	}
+}
+
+// Diff returns a line-oriented diff between want and got.
+// Lines only in want are prefixed with "-", lines only in got with "+",
+// and common lines with a space.
+func Diff(want, got string) string {
+	a := splitLines(want)
+	b := splitLines(got)
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("--- want\n+++ got\n")
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(&sb, " %s\n", a[i])
+			i, j = i+1, j+1
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			fmt.Fprintf(&sb, "+%s\n", b[j])
+			j++
+		default:
+			fmt.Fprintf(&sb, "-%s\n", a[i])
+			i++
+		}
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// Text renders v in a stable textual form suitable for golden files.
+//
+// Descriptors are rendered as their kind followed by their full name (files by their path),
+// messages as their populated fields in field-number order, and map entries in key order.
+// Other values are rendered with [fmt.Sprint].
+func Text(v any) string {
+	var sb strings.Builder
+	writeText(&sb, v)
+	return sb.String()
+}
+
+func writeText(sb *strings.Builder, v any) {
+	switch v := v.(type) {
+	case nil:
+		sb.WriteString("<nil>")
+	case protoreflect.FileDescriptor:
+		sb.WriteString("file " + v.Path())
+	case protoreflect.Descriptor:
+		kind := reflectutil.Kind(v)
+		if kind == "" {
+			kind = "descriptor"
+		}
+		sb.WriteString(kind + " " + string(v.FullName()))
+	case protoreflect.Message:
+		writeMessage(sb, v)
+	case protoreflect.ProtoMessage:
+		writeMessage(sb, v.ProtoReflect())
+	case protoreflect.Value:
+		writeText(sb, v.Interface())
+	case protoreflect.MapKey:
+		writeText(sb, v.Interface())
+	case protoreflect.List:
+		sb.WriteString("[")
+		for i := range v.Len() {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			writeText(sb, v.Get(i))
+		}
+		sb.WriteString("]")
+	case protoreflect.Map:
+		keys := make([]protoreflect.MapKey, 0, v.Len())
+		v.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, k)
+			return true
+		})
+		slices.SortFunc(keys, reflectutil.CompareMapKeys)
+		sb.WriteString("{")
+		for i, k := range keys {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			writeText(sb, k)
+			sb.WriteString(": ")
+			writeText(sb, v.Get(k))
+		}
+		sb.WriteString("}")
+	case string:
+		fmt.Fprintf(sb, "%q", v)
+	case []byte:
+		fmt.Fprintf(sb, "%q", v)
+	default:
+		fmt.Fprint(sb, v)
+	}
+}
+
+func writeMessage(sb *strings.Builder, m protoreflect.Message) {
+	var fields []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	slices.SortFunc(fields, func(x, y protoreflect.FieldDescriptor) int {
+		return cmp.Compare(x.Number(), y.Number())
+	})
+	sb.WriteString("{")
+	for i, fd := range fields {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		if fd.IsExtension() {
+			sb.WriteString("[" + string(fd.FullName()) + "]")
+		} else {
+			sb.WriteString(fd.TextName())
+		}
+		sb.WriteString(": ")
+		writeText(sb, m.Get(fd))
+	}
+	sb.WriteString("}")
+}
@@ -0,0 +1,85 @@
+package protoitertest_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/protoiter/protoitertest"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func ExampleText() {
+	now := timestamppb.New(time.Unix(123, 456))
+	fmt.Println(protoitertest.Text(now))
+	fmt.Println(protoitertest.Text(now.ProtoReflect().Descriptor()))
+	// Output:
+	// {seconds: 123 nanos: 456}
+	// message google.protobuf.Timestamp
+}
+
+func ExampleDiff() {
+	fmt.Print(protoitertest.Diff("a\nb\nc\n", "a\nc\nd\n"))
+	// Output:
+	// --- want
+	// +++ got
+	//  a
+	// -b
+	//  c
+	// +d
+}
+
+func TestGolden(t *testing.T) {
+	var _ durationpb.Duration
+	file := results.Must1(protoregistry.GlobalFiles.FindFileByPath("google/protobuf/duration.proto"))
+	protoitertest.Golden2(t, "testdata/duration.golden", protoiter.Each(file.Messages()))
+}
+
+func TestText(t *testing.T) {
+	s := results.Must1(structpb.NewStruct(map[string]any{"b": 1, "a": []any{"x", true}}))
+	got := protoitertest.Text(s)
+	want := `{fields: {"a": {list_value: {values: [{string_value: "x"}, {bool_value: true}]}}, "b": {number_value: 1}}}`
+	if got != want {
+		t.Errorf("must be equal\ngot\t%s\nwant\t%s", got, want)
+	}
+}
+
+type recordingTB struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *recordingTB) Fatalf(format string, args ...any) {
+	tb.Errorf(format, args...)
+	tb.fatal = true
+}
+
+func TestGoldenMismatch(t *testing.T) {
+	tb := &recordingTB{TB: t}
+	protoitertest.Golden(tb, "testdata/duration.golden", func(yield func(string) bool) {
+		yield("0\tmessage google.protobuf.Timestamp")
+	})
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], "-0\tmessage google.protobuf.Duration") {
+		t.Errorf("must report a diff, got %q", tb.errors)
+	}
+
+	tb = &recordingTB{TB: t}
+	protoitertest.Golden(tb, filepath.Join(t.TempDir(), "missing.golden"), func(func(int) bool) {})
+	if !tb.fatal {
+		t.Error("must fail when the golden file is missing")
+	}
+}
@@ -0,0 +1,250 @@
+package protoiter
+
+import (
+	"fmt"
+	"iter"
+	"strconv"
+	"strings"
+
+	"github.com/goaux/protoiter/internal/reflectutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Query is a compiled descriptor query expression.
+//
+// An expression is a sequence of steps separated by "/":
+//
+//	step  = kind [ "(" glob ")" ] { "[" attr "=" value "]" }
+//	kind  = "file" | "message" | "field" | "oneof" | "enum" | "enum_value" | "extension" | "service" | "method" | "*"
+//
+// The first step selects matching descriptors anywhere in the registry,
+// and each following step selects matching descriptors declared directly within those selected by the previous step.
+// The glob is matched against the full name (the path for files) as described for [EachMatching].
+// The attributes are:
+//
+//	name       glob matched against the short name
+//	number     field or enum value number
+//	type       field kind, e.g. bytes, message, enum, int32
+//	label      field cardinality: optional, required or repeated
+//	type_name  glob matched against the full name of the field's message or enum type
+//	map        true or false, whether the field is a map
+//
+// For example, "message(acme.**) / field[type=bytes]" selects the bytes fields of every message in package acme and its subpackages.
+type Query struct {
+	expr  string
+	steps []queryStep
+}
+
+type queryStep struct {
+	kind  string
+	glob  string
+	attrs []queryAttr
+}
+
+type queryAttr struct {
+	name, value string
+}
+
+var queryKinds = map[string]bool{
+	"file": true, "message": true, "field": true, "oneof": true, "enum": true,
+	"enum_value": true, "extension": true, "service": true, "method": true, "*": true,
+}
+
+var queryAttrs = map[string]bool{
+	"name": true, "number": true, "type": true, "label": true, "type_name": true, "map": true,
+}
+
+// ParseQuery compiles a descriptor query expression.
+//
+// Parameters:
+//   - expr: The query expression, see [Query] for the syntax
+//
+// Returns:
+//   - The compiled query, or an error describing the first syntax error
+func ParseQuery(expr string) (*Query, error) {
+	q := &Query{expr: expr}
+	for _, s := range splitQuery(expr) {
+		step, err := parseQueryStep(s)
+		if err != nil {
+			return nil, fmt.Errorf("protoiter: invalid query %q: %w", expr, err)
+		}
+		q.steps = append(q.steps, step)
+	}
+	return q, nil
+}
+
+// MustParseQuery is like [ParseQuery] but panics if the expression cannot be parsed.
+func MustParseQuery(expr string) *Query {
+	q, err := ParseQuery(expr)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// String returns the source expression of the query.
+func (q *Query) String() string {
+	return q.expr
+}
+
+// splitQuery splits expr at the "/" separators outside parentheses and brackets.
+func splitQuery(expr string) []string {
+	var steps []string
+	depth, start := 0, 0
+	for i := range len(expr) {
+		switch expr[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '/':
+			if depth == 0 {
+				steps = append(steps, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(steps, expr[start:])
+}
+
+func parseQueryStep(s string) (step queryStep, err error) {
+	s = strings.TrimSpace(s)
+	end := strings.IndexAny(s, "([")
+	if end < 0 {
+		end = len(s)
+	}
+	step.kind = strings.TrimSpace(s[:end])
+	if !queryKinds[step.kind] {
+		return step, fmt.Errorf("unknown kind %q", step.kind)
+	}
+	s = strings.TrimSpace(s[end:])
+	if strings.HasPrefix(s, "(") {
+		end := strings.IndexByte(s, ')')
+		if end < 0 {
+			return step, fmt.Errorf("missing ) in %q", s)
+		}
+		step.glob = strings.TrimSpace(s[1:end])
+		s = strings.TrimSpace(s[end+1:])
+	}
+	for s != "" {
+		if !strings.HasPrefix(s, "[") {
+			return step, fmt.Errorf("unexpected %q", s)
+		}
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return step, fmt.Errorf("missing ] in %q", s)
+		}
+		name, value, ok := strings.Cut(s[1:end], "=")
+		if !ok {
+			return step, fmt.Errorf("missing = in %q", s[:end+1])
+		}
+		attr := queryAttr{name: strings.TrimSpace(name), value: strings.TrimSpace(value)}
+		if !queryAttrs[attr.name] {
+			return step, fmt.Errorf("unknown attribute %q", attr.name)
+		}
+		step.attrs = append(step.attrs, attr)
+		s = strings.TrimSpace(s[end+1:])
+	}
+	return step, nil
+}
+
+// Each creates a sequential iterator over the descriptors in a registry selected by the query.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields each selected descriptor
+func (q *Query) Each(files Files) iter.Seq[protoreflect.Descriptor] {
+	return func(yield func(protoreflect.Descriptor) bool) {
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			if !q.steps[0].match(d) {
+				return true
+			}
+			return q.descend(d, q.steps[1:], yield)
+		})
+	}
+}
+
+func (q *Query) descend(d protoreflect.Descriptor, steps []queryStep, yield func(protoreflect.Descriptor) bool) bool {
+	if len(steps) == 0 {
+		return yield(d)
+	}
+	return eachChild(d, func(child protoreflect.Descriptor) bool {
+		if !steps[0].match(child) {
+			return true
+		}
+		return q.descend(child, steps[1:], yield)
+	})
+}
+
+// EachQuery creates a sequential iterator over the descriptors in a registry selected by a query expression.
+//
+// It panics if the expression cannot be parsed; use [ParseQuery] to handle syntax errors.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - expr: The query expression, see [Query] for the syntax
+//
+// Returns:
+//   - An iterator sequence that yields each selected descriptor
+func EachQuery(files Files, expr string) iter.Seq[protoreflect.Descriptor] {
+	return MustParseQuery(expr).Each(files)
+}
+
+func (step queryStep) match(d protoreflect.Descriptor) bool {
+	if step.kind != "*" && step.kind != reflectutil.Kind(d) {
+		return false
+	}
+	if step.glob != "" {
+		if fd, ok := d.(protoreflect.FileDescriptor); ok {
+			if !matchGlob(step.glob, fd.Path(), '/') {
+				return false
+			}
+		} else if !matchGlob(step.glob, string(d.FullName()), '.') {
+			return false
+		}
+	}
+	for _, attr := range step.attrs {
+		if !attr.match(d) {
+			return false
+		}
+	}
+	return true
+}
+
+func (attr queryAttr) match(d protoreflect.Descriptor) bool {
+	switch attr.name {
+	case "name":
+		return matchGlob(attr.value, string(d.Name()), '.')
+	case "number":
+		switch d := d.(type) {
+		case protoreflect.FieldDescriptor:
+			return attr.value == strconv.Itoa(int(d.Number()))
+		case protoreflect.EnumValueDescriptor:
+			return attr.value == strconv.Itoa(int(d.Number()))
+		}
+		return false
+	}
+	fd, ok := d.(protoreflect.FieldDescriptor)
+	if !ok {
+		return false
+	}
+	switch attr.name {
+	case "type":
+		return attr.value == fd.Kind().String()
+	case "label":
+		return attr.value == fd.Cardinality().String()
+	case "type_name":
+		switch {
+		case fd.Message() != nil:
+			return matchGlob(attr.value, string(fd.Message().FullName()), '.')
+		case fd.Enum() != nil:
+			return matchGlob(attr.value, string(fd.Enum().FullName()), '.')
+		}
+		return false
+	case "map":
+		return attr.value == strconv.FormatBool(fd.IsMap())
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const acmeProto = `
+	name: "acme/store.proto"
+	package: "acme.store"
+	message_type {
+		name: "Blob"
+		field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "data" number: 2 label: LABEL_OPTIONAL type: TYPE_BYTES }
+		field { name: "parts" number: 3 label: LABEL_REPEATED type: TYPE_BYTES }
+		nested_type {
+			name: "Meta"
+			field { name: "digest" number: 1 label: LABEL_OPTIONAL type: TYPE_BYTES }
+			field { name: "state" number: 2 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".acme.store.State" }
+		}
+	}
+	enum_type {
+		name: "State"
+		value { name: "STATE_UNSPECIFIED" number: 0 }
+		value { name: "STATE_READY" number: 1 }
+	}
+	service {
+		name: "BlobService"
+		method { name: "Get" input_type: ".acme.store.Blob" output_type: ".acme.store.Blob" }
+	}
+`
+
+func ExampleEachQuery() {
+	files := newFiles(acmeProto)
+	for d := range protoiter.EachQuery(files, "message(acme.**) / field[type=bytes]") {
+		fmt.Println(d.FullName())
+	}
+	// Output:
+	// acme.store.Blob.data
+	// acme.store.Blob.parts
+	// acme.store.Blob.Meta.digest
+}
+
+func TestQuery(t *testing.T) {
+	files := newFiles(acmeProto)
+	tests := []struct {
+		expr string
+		want []protoreflect.FullName
+	}{
+		{"message(acme.*)", nil},
+		{"message(acme.*.*)", []protoreflect.FullName{"acme.store.Blob"}},
+		{"message(**Meta)", []protoreflect.FullName{"acme.store.Blob.Meta"}},
+		{"file(acme/*.proto) / message / field[label=repeated]", []protoreflect.FullName{"acme.store.Blob.parts"}},
+		{"field[type_name=acme.**.State]", []protoreflect.FullName{"acme.store.Blob.Meta.state"}},
+		{"enum / enum_value[number=1]", []protoreflect.FullName{"acme.store.STATE_READY"}},
+		{"service / method[name=G*]", []protoreflect.FullName{"acme.store.BlobService.Get"}},
+		{"* ( acme.store.Blob ) / *[name=id]", []protoreflect.FullName{"acme.store.Blob.id"}},
+		{"field[map=true]", nil},
+	}
+	for _, tt := range tests {
+		q, err := protoiter.ParseQuery(tt.expr)
+		if err != nil {
+			t.Errorf("%q: %v", tt.expr, err)
+			continue
+		}
+		var got []protoreflect.FullName
+		for d := range q.Each(files) {
+			got = append(got, d.FullName())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q: must be equal\ngot\t%v\nwant\t%v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryError(t *testing.T) {
+	for _, expr := range []string{"", "msg", "message(acme", "message[type=bytes", "message[type]", "field[color=red]", "field x"} {
+		if _, err := protoiter.ParseQuery(expr); err == nil {
+			t.Errorf("%q: must fail", expr)
+		}
+	}
+}
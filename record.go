@@ -0,0 +1,112 @@
+package protoiter
+
+import (
+	"encoding/base64"
+	"iter"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/reflect/protorange"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachRecord creates a sequential iterator over the populated leaves of a message flattened into column name and value pairs,
+// suitable for CSV or TSV export.
+//
+// The leaves are those visited by [EachPath]. A column name is the leaf's path without its root step,
+// such as "name", "field[0].number" or `labels["env"]`.
+// Values are formatted as text: strings as they are, bytes in standard base64, enums by value name when known,
+// numbers and booleans with [strconv], and messages without populated fields as the empty string.
+//
+// If columns is nil, every populated leaf is yielded in path order.
+// Otherwise exactly the given columns are yielded in the given order, with an empty value for columns that are not populated,
+// so records of the same message type line up as rows.
+//
+// Parameters:
+//   - message: The protocol buffer message to flatten
+//   - columns: The column names to yield, or nil for all populated leaves
+//
+// Returns:
+//   - An iterator sequence that yields each column name and its formatted value
+func EachRecord(message protoreflect.Message, columns []string) iter.Seq2[string, string] {
+	if columns == nil {
+		return func(yield func(string, string) bool) {
+			eachLeaf(message, func(column, value string) bool {
+				return yield(column, value)
+			})
+		}
+	}
+	return func(yield func(string, string) bool) {
+		values := make(map[string]string)
+		eachLeaf(message, func(column, value string) bool {
+			values[column] = value
+			return true
+		})
+		for _, column := range columns {
+			if !yield(column, values[column]) {
+				return
+			}
+		}
+	}
+}
+
+func eachLeaf(message protoreflect.Message, f func(column, value string) bool) {
+	noAnyExpansion.Range(message, func(p protopath.Values) error {
+		last := p.Index(-1)
+		if len(p.Path) == 1 || !isLeaf(last.Value) {
+			return nil
+		}
+		if !f(columnName(p.Path), formatLeaf(p)) {
+			return protorange.Terminate
+		}
+		return nil
+	}, nil)
+}
+
+// columnName returns the path without its root step and leading dot.
+func columnName(path protopath.Path) string {
+	return strings.TrimPrefix(path[1:].String(), ".")
+}
+
+// formatLeaf formats the last value of p as text.
+func formatLeaf(p protopath.Values) string {
+	last := p.Index(-1)
+	var fd protoreflect.FieldDescriptor
+	switch last.Step.Kind() {
+	case protopath.FieldAccessStep:
+		fd = last.Step.FieldDescriptor()
+	case protopath.ListIndexStep:
+		fd = p.Index(-2).Step.FieldDescriptor()
+	case protopath.MapIndexStep:
+		fd = p.Index(-2).Step.FieldDescriptor().MapValue()
+	}
+	switch v := last.Value.Interface().(type) {
+	case string:
+		return v
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case protoreflect.EnumNumber:
+		if fd != nil && fd.Enum() != nil {
+			if ev := fd.Enum().Values().ByNumber(v); ev != nil {
+				return string(ev.Name())
+			}
+		}
+		return strconv.Itoa(int(v))
+	case bool:
+		return strconv.FormatBool(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func ExampleEachRecord() {
+	m := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String("id"),
+		Number: proto.Int32(1),
+		Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+	}
+	for column, value := range protoiter.EachRecord(m.ProtoReflect(), nil) {
+		fmt.Printf("%s=%s\n", column, value)
+	}
+	for column, value := range protoiter.EachRecord(m.ProtoReflect(), []string{"name", "json_name"}) {
+		fmt.Printf("%s=%q\n", column, value)
+	}
+	// Output:
+	// name=id
+	// number=1
+	// type=TYPE_STRING
+	// name="id"
+	// json_name=""
+}
+
+func TestEachRecord(t *testing.T) {
+	s := results.Must1(structpb.NewStruct(map[string]any{"n": 1.5, "l": []any{true}}))
+	var got []string
+	for column, value := range protoiter.EachRecord(s.ProtoReflect(), nil) {
+		got = append(got, column+"="+value)
+	}
+	want := []string{
+		`fields["l"].list_value.values[0].bool_value=true`,
+		`fields["n"].number_value=1.5`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	b := wrapperspb.Bytes([]byte("hi"))
+	for column, value := range protoiter.EachRecord(b.ProtoReflect(), nil) {
+		if column != "value" || value != "aGk=" {
+			t.Errorf("bytes must be base64, got %s=%s", column, value)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package protoiter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// LengthPrefix is the encoding of the length preceding each binary record of a [Framing].
+type LengthPrefix int
+
+const (
+	// VarintPrefix is a varint, as written by [google.golang.org/protobuf/encoding/protodelim].
+	VarintPrefix LengthPrefix = iota
+
+	// Fixed32BigEndian is a 4-byte big-endian unsigned integer, as used by many RPC and log formats.
+	Fixed32BigEndian
+
+	// Fixed32LittleEndian is a 4-byte little-endian unsigned integer.
+	Fixed32LittleEndian
+)
+
+// Framing describes how the records of a stream are delimited, as converted by [Reframe].
+//
+// The zero value is a stream of varint length-delimited binary messages, as read by [EachDelimited].
+type Framing struct {
+	// Prefix is the encoding of the length of each binary record.
+	Prefix LengthPrefix
+
+	// JSON, if not nil, makes the stream newline-delimited JSON, as written by [WriteJSONL],
+	// with each line holding a message of this type. Prefix is then ignored.
+	JSON protoreflect.MessageType
+}
+
+func (f Framing) validate() error {
+	if f.JSON == nil && (f.Prefix < VarintPrefix || f.Prefix > Fixed32LittleEndian) {
+		return fmt.Errorf("protoiter: unknown length prefix %d", f.Prefix)
+	}
+	return nil
+}
+
+func (f Framing) byteOrder() binary.ByteOrder {
+	if f.Prefix == Fixed32LittleEndian {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// Reframe copies the records of a stream from one framing to another, e.g. from a varint-delimited archive
+// to 4-byte length prefixes or to newline-delimited JSON.
+//
+// The records are streamed one at a time, so the memory used does not depend on the length of the stream.
+// Binary records are copied as they are between binary framings, and are only decoded when reading or writing JSON,
+// with the message types of the framings. Records larger than [DefaultMaxMessageSize] are rejected, and blank lines
+// of JSON input are skipped. Reframing stops at the first error; the records converted before it have been written.
+//
+// Parameters:
+//   - dst: The writer receiving the converted stream
+//   - src: The stream to convert
+//   - from: The framing of src
+//   - to: The framing to write
+//
+// Returns:
+//   - The number of records written, and the first read, decoding or write error, if any
+func Reframe(dst io.Writer, src io.Reader, from, to Framing) (int, error) {
+	if err := errors.Join(from.validate(), to.validate()); err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(dst)
+	var buf []byte
+	n := 0
+	for record, err := range eachFramedRecord(src, from) {
+		if err != nil {
+			return n, errors.Join(err, w.Flush())
+		}
+		if buf, err = appendFramed(buf[:0], record, to); err != nil {
+			return n, errors.Join(fmt.Errorf("protoiter: record %d: %w", n, err), w.Flush())
+		}
+		if _, err := w.Write(buf); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, w.Flush()
+}
+
+// eachFramedRecord yields the binary messages of a stream with framing f, each valid until the next one is read.
+func eachFramedRecord(r io.Reader, f Framing) iter.Seq2[[]byte, error] {
+	switch {
+	case f.JSON != nil:
+		return eachJSONRecord(r, f.JSON)
+	case f.Prefix == VarintPrefix:
+		return EachDelimitedRecord(r, DelimitedOptions{ZeroCopy: true})
+	}
+	return eachFixed32Record(r, f.byteOrder())
+}
+
+func eachFixed32Record(r io.Reader, order binary.ByteOrder) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		br := bufio.NewReader(r)
+		var offset int64
+		var prefix [4]byte
+		var buf []byte
+		for {
+			if _, err := io.ReadFull(br, prefix[:]); err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(nil, fmt.Errorf("protoiter: record at offset %d: %w", offset, err))
+				}
+				return
+			}
+			size := order.Uint32(prefix[:])
+			if size > DefaultMaxMessageSize {
+				yield(nil, fmt.Errorf("protoiter: record at offset %d: size %d exceeds the limit %d: %w", offset, size, DefaultMaxMessageSize, ErrMessageTooLarge))
+				return
+			}
+			buf = slices.Grow(buf[:0], int(size))[:size]
+			if _, err := io.ReadFull(br, buf); err != nil {
+				if errors.Is(err, io.EOF) {
+					err = io.ErrUnexpectedEOF
+				}
+				yield(nil, fmt.Errorf("protoiter: record at offset %d: %w", offset, err))
+				return
+			}
+			if !yield(buf, nil) {
+				return
+			}
+			offset += int64(len(prefix) + len(buf))
+		}
+	}
+}
+
+func eachJSONRecord(r io.Reader, mt protoreflect.MessageType) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		s := bufio.NewScanner(r)
+		s.Buffer(nil, DefaultMaxMessageSize)
+		var buf []byte
+		for line := 1; s.Scan(); line++ {
+			text := bytes.TrimSpace(s.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+			m := mt.New().Interface()
+			err := protojson.Unmarshal(text, m)
+			if err == nil {
+				buf, err = proto.MarshalOptions{}.MarshalAppend(buf[:0], m)
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("protoiter: line %d: %w", line, err))
+				return
+			}
+			if !yield(buf, nil) {
+				return
+			}
+		}
+		if err := s.Err(); err != nil {
+			yield(nil, fmt.Errorf("protoiter: %w", err))
+		}
+	}
+}
+
+// appendFramed appends a binary message to b framed as f.
+func appendFramed(b, record []byte, f Framing) ([]byte, error) {
+	switch {
+	case f.JSON != nil:
+		m := f.JSON.New().Interface()
+		if err := proto.Unmarshal(record, m); err != nil {
+			return b, err
+		}
+		b, err := protojson.MarshalOptions{}.MarshalAppend(b, m)
+		return append(b, '\n'), err
+	case f.Prefix == VarintPrefix:
+		b = protowire.AppendVarint(b, uint64(len(record)))
+	default:
+		var prefix [4]byte
+		f.byteOrder().PutUint32(prefix[:], uint32(len(record)))
+		b = append(b, prefix[:]...)
+	}
+	return append(b, record...), nil
+}
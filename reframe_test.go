@@ -0,0 +1,90 @@
+package protoiter_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func ExampleReframe() {
+	archive := delimited(durationpb.New(1500000000), durationpb.New(-2000000))
+	jsonl := protoiter.Framing{JSON: (*durationpb.Duration)(nil).ProtoReflect().Type()}
+	n, err := protoiter.Reframe(os.Stdout, bytes.NewReader(archive), protoiter.Framing{}, jsonl)
+	fmt.Println(n, err)
+	// Output:
+	// "1.500s"
+	// "-0.002s"
+	// 2 <nil>
+}
+
+func TestReframe(t *testing.T) {
+	durationType := (*durationpb.Duration)(nil).ProtoReflect().Type()
+	archive := delimited(durationpb.New(1), durationpb.New(0), durationpb.New(3))
+
+	// Convert through every framing and back.
+	framings := []protoiter.Framing{
+		{Prefix: protoiter.Fixed32BigEndian},
+		{Prefix: protoiter.Fixed32LittleEndian},
+		{JSON: durationType},
+		{},
+	}
+	from, stream := protoiter.Framing{}, archive
+	for _, to := range framings {
+		var buf bytes.Buffer
+		n, err := protoiter.Reframe(&buf, bytes.NewReader(stream), from, to)
+		if n != 3 || err != nil {
+			t.Fatalf("Reframe(%+v) = %d, %v", to, n, err)
+		}
+		from, stream = to, buf.Bytes()
+	}
+	if !bytes.Equal(stream, archive) {
+		t.Errorf("the round trip must restore the archive\ngot\t%x\nwant\t%x", stream, archive)
+	}
+
+	var buf bytes.Buffer
+	fixed := protoiter.Framing{Prefix: protoiter.Fixed32BigEndian}
+	if _, err := protoiter.Reframe(&buf, bytes.NewReader(archive), protoiter.Framing{}, fixed); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.Bytes()[:4], []byte{0, 0, 0, 2}; !bytes.Equal(got, want) {
+		t.Errorf("the first prefix must be %x, got %x", want, got)
+	}
+	truncated := buf.Bytes()[:buf.Len()-1]
+	n, err := protoiter.Reframe(&bytes.Buffer{}, bytes.NewReader(truncated), fixed, protoiter.Framing{})
+	if n != 2 || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("a truncated record must fail after the complete ones, got %d, %v", n, err)
+	}
+	huge := []byte{0xff, 0xff, 0xff, 0xff}
+	if _, err := protoiter.Reframe(&bytes.Buffer{}, bytes.NewReader(huge), fixed, protoiter.Framing{}); !errors.Is(err, protoiter.ErrMessageTooLarge) {
+		t.Errorf("an oversized record must be rejected, got %v", err)
+	}
+
+	lines := "\"1s\"\n\n\"bad\"\n"
+	buf.Reset()
+	n, err = protoiter.Reframe(&buf, strings.NewReader(lines), protoiter.Framing{JSON: durationType}, protoiter.Framing{})
+	if n != 1 || err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("a malformed line must be reported by number, got %d, %v", n, err)
+	}
+	if buf.Len() == 0 {
+		t.Error("the records converted before an error must be written")
+	}
+
+	malformed := []byte{1, 0xff}
+	if _, err := protoiter.Reframe(&bytes.Buffer{}, bytes.NewReader(malformed), protoiter.Framing{}, protoiter.Framing{JSON: durationType}); err == nil {
+		t.Error("a malformed record must fail to decode")
+	}
+
+	if _, err := protoiter.Reframe(&bytes.Buffer{}, bytes.NewReader(archive), protoiter.Framing{Prefix: 9}, protoiter.Framing{}); err == nil {
+		t.Error("an unknown prefix must be rejected")
+	}
+	if _, err := protoiter.Reframe(failingWriter{}, bytes.NewReader(archive), protoiter.Framing{}, fixed); err == nil {
+		t.Error("a write error must be returned")
+	}
+}
@@ -0,0 +1,259 @@
+// Package remote provides [protoiter.Files] implementations backed by schemas served over HTTP,
+// so the same Each* code in protoiter can consume local and remote schemas alike.
+//
+// [Registry] fetches a whole FileDescriptorSet with a single GET on first use, such as the image download
+// of the Buf Schema Registry or a set published as a static file.
+// [SubjectRegistry] pulls schemas one subject at a time from a Confluent-style schema registry,
+// fetching each file, and the files it references, only when it is first looked up.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var _ protoiter.Files = (*Registry)(nil)
+
+const (
+	// DefaultTimeout bounds a fetch, including reading the body, when the Timeout of a registry is zero.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultMaxBodySize is the largest response body accepted when the MaxBodySize of a registry is zero.
+	DefaultMaxBodySize = 64 << 20
+)
+
+// ErrBodyTooLarge is wrapped by the error of a fetch whose response body exceeds the MaxBodySize of its registry.
+var ErrBodyTooLarge = errors.New("remote: response body too large")
+
+// Registry pulls a FileDescriptorSet from an HTTP endpoint and exposes the resolved files.
+//
+// The zero value is not usable; URL must be set.
+// A Registry is safe for concurrent use.
+type Registry struct {
+	// URL is the endpoint returning the FileDescriptorSet.
+	URL string
+
+	// Client is the HTTP client used for the request.
+	// If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// Timeout bounds the fetch, including reading the body, in addition to any deadline of its context
+	// and any timeout of Client. If zero, DefaultTimeout is used; if negative, the fetch is not bounded.
+	Timeout time.Duration
+
+	// MaxBodySize is the largest response body accepted, in bytes.
+	// If zero or negative, DefaultMaxBodySize is used.
+	MaxBodySize int64
+
+	// Decode converts a response body into a FileDescriptorSet.
+	// If nil, a body with an application/json content type is decoded with protojson
+	// and any other body as a binary FileDescriptorSet.
+	// Endpoints serving a single set as .proto sources need a Decode that compiles them.
+	Decode func(contentType string, body []byte) (*descriptorpb.FileDescriptorSet, error)
+
+	// Resolver resolves imports that are not part of the fetched set.
+	// If nil, protoregistry.GlobalFiles is used.
+	Resolver protodesc.Resolver
+
+	mu    sync.Mutex
+	files *protoregistry.Files
+	err   error
+}
+
+// Load returns the resolved files, fetching them on the first call.
+//
+// The result is cached until [Registry.Reset], whether the fetch succeeded or failed,
+// so a failing endpoint is not requested again on every use. A fetch that failed
+// because ctx was canceled or expired, or because it ran out of [Registry.Timeout],
+// is not cached and is retried on the next call.
+func (r *Registry) Load(ctx context.Context) (*protoregistry.Files, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.files != nil || r.err != nil {
+		return r.files, r.err
+	}
+	files, err := r.fetch(ctx)
+	if err != nil && interrupted(ctx, err) {
+		return nil, err
+	}
+	r.files, r.err = files, err
+	return r.files, r.err
+}
+
+// interrupted reports whether a fetch failed because ctx or the timeout of the fetch ended it,
+// rather than because of the endpoint, so that the failure is worth retrying.
+func interrupted(ctx context.Context, err error) bool {
+	return ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// Reset drops the cached files so the next use fetches them again.
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files, r.err = nil, nil
+}
+
+// Err returns the cached error of the last fetch, or nil if it succeeded or has not happened.
+//
+// RangeFiles and RangeFilesByPackage cannot report errors; they iterate over nothing when the fetch fails,
+// and Err reports why.
+func (r *Registry) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// EachFile creates a sequential iterator over the remote file descriptors.
+//
+// If the fetch fails, the iterator yields a single nil descriptor with the error.
+//
+// Parameters:
+//   - ctx: The context of the HTTP request, used only if the files are not cached yet
+//
+// Returns:
+//   - An iterator sequence that yields each file descriptor, or an error
+func (r *Registry) EachFile(ctx context.Context) iter.Seq2[protoreflect.FileDescriptor, error] {
+	return func(yield func(protoreflect.FileDescriptor, error) bool) {
+		files, err := r.Load(ctx)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			return yield(fd, nil)
+		})
+	}
+}
+
+// RangeFiles implements [protoiter.Files].
+func (r *Registry) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	if files, err := r.Load(context.Background()); err == nil {
+		files.RangeFiles(f)
+	}
+}
+
+// RangeFilesByPackage implements [protoiter.Files].
+func (r *Registry) RangeFilesByPackage(name protoreflect.FullName, f func(protoreflect.FileDescriptor) bool) {
+	if files, err := r.Load(context.Background()); err == nil {
+		files.RangeFilesByPackage(name, f)
+	}
+}
+
+func (r *Registry) fetch(ctx context.Context) (*protoregistry.Files, error) {
+	contentType, body, err := get(ctx, r.Client, r.URL, r.Timeout, r.MaxBodySize)
+	if err != nil {
+		return nil, err
+	}
+	decode := r.Decode
+	if decode == nil {
+		decode = decodeFileDescriptorSet
+	}
+	set, err := decode(contentType, body)
+	if err != nil {
+		return nil, fmt.Errorf("remote: decode %s: %w", r.URL, err)
+	}
+	resolver := r.Resolver
+	if resolver == nil {
+		resolver = protoregistry.GlobalFiles
+	}
+	files, err := protodesc.FileOptions{}.NewFiles(withImports(set, resolver))
+	if err != nil {
+		return nil, fmt.Errorf("remote: resolve %s: %w", r.URL, err)
+	}
+	return files, nil
+}
+
+// get fetches url and returns the content type and body of the response,
+// applying the defaults of [Registry] to a nil client, a zero timeout and a non-positive body limit.
+func get(ctx context.Context, client *http.Client, url string, timeout time.Duration, limit int64) (string, []byte, error) {
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("remote: GET %s: %s", url, resp.Status)
+	}
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return "", nil, err
+	}
+	if int64(len(body)) > limit {
+		return "", nil, fmt.Errorf("remote: GET %s: more than %d bytes: %w", url, limit, ErrBodyTooLarge)
+	}
+	return resp.Header.Get("Content-Type"), body, nil
+}
+
+func decodeFileDescriptorSet(contentType string, body []byte) (*descriptorpb.FileDescriptorSet, error) {
+	set := new(descriptorpb.FileDescriptorSet)
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/json" {
+		return set, protojson.Unmarshal(body, set)
+	}
+	return set, proto.Unmarshal(body, set)
+}
+
+// withImports returns set extended with the imports it lacks, looked up in resolver.
+// protodesc.NewFiles requires a self-contained set, but remote registries
+// commonly omit well-known and other shared imports.
+func withImports(set *descriptorpb.FileDescriptorSet, resolver protodesc.Resolver) *descriptorpb.FileDescriptorSet {
+	present := make(map[string]bool)
+	for _, file := range set.GetFile() {
+		present[file.GetName()] = true
+	}
+	out := &descriptorpb.FileDescriptorSet{File: set.GetFile()}
+	var add func(path string)
+	add = func(path string) {
+		if present[path] {
+			return
+		}
+		present[path] = true
+		fd, err := resolver.FindFileByPath(path)
+		if err != nil {
+			return // reported by NewFiles as an unresolvable import
+		}
+		imports := fd.Imports()
+		for i := range imports.Len() {
+			add(imports.Get(i).Path())
+		}
+		out.File = append(out.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, file := range set.GetFile() {
+		for _, dep := range file.GetDependency() {
+			add(dep)
+		}
+	}
+	return out
+}
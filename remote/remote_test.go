@@ -0,0 +1,175 @@
+package remote_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/protoiter/remote"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func fileDescriptorSet() *descriptorpb.FileDescriptorSet {
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{{
+			Name:       proto.String("event.proto"),
+			Package:    proto.String("test"),
+			Dependency: []string{"google/protobuf/timestamp.proto"},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("Event"),
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:     proto.String("at"),
+					Number:   proto.Int32(1),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					TypeName: proto.String(".google.protobuf.Timestamp"),
+				}},
+			}},
+		}},
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	var _ timestamppb.Timestamp
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		b, _ := proto.Marshal(fileDescriptorSet())
+		w.Write(b)
+	}))
+	defer server.Close()
+
+	registry := &remote.Registry{URL: server.URL}
+	var got []string
+	for file := range protoiter.EachFileByPackage(registry, "test") {
+		got = append(got, file.Path())
+	}
+	if !slices.Equal(got, []string{"event.proto"}) {
+		t.Errorf("unexpected files %v", got)
+	}
+	n := 0
+	for _, err := range registry.EachFile(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("must include the resolved import, got %d files", n)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("must fetch once, got %d requests", requests.Load())
+	}
+	registry.Reset()
+	for range protoiter.EachFile(registry) {
+	}
+	if requests.Load() != 2 {
+		t.Errorf("must fetch again after Reset, got %d requests", requests.Load())
+	}
+}
+
+func TestRegistryJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := protojson.Marshal(fileDescriptorSet())
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(b)
+	}))
+	defer server.Close()
+
+	files, err := (&remote.Registry{URL: server.URL}).Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fd, err := files.FindFileByPath("event.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(protodesc.ToFileDescriptorProto(fd), fileDescriptorSet().File[0]) {
+		t.Error("must round-trip the file")
+	}
+}
+
+func TestRegistryError(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	registry := &remote.Registry{URL: server.URL}
+	for range protoiter.EachFile(registry) {
+		t.Error("must not yield files")
+	}
+	if registry.Err() == nil {
+		t.Error("must record the error")
+	}
+	for file, err := range registry.EachFile(context.Background()) {
+		if file != nil || err == nil {
+			t.Errorf("must yield the error, got %v, %v", file, err)
+		}
+	}
+	for range protoiter.EachFile(registry) {
+	}
+	if requests.Load() != 1 {
+		t.Errorf("a failed fetch must be cached, got %d requests", requests.Load())
+	}
+	registry.Reset()
+	registry.Load(context.Background())
+	if requests.Load() != 2 {
+		t.Errorf("must fetch again after Reset, got %d requests", requests.Load())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	registry.Reset()
+	if _, err := registry.Load(ctx); err == nil {
+		t.Error("a canceled fetch must fail")
+	}
+	if registry.Err() != nil {
+		t.Errorf("a canceled fetch must not be cached, got %v", registry.Err())
+	}
+}
+
+func TestRegistryLimits(t *testing.T) {
+	stall := make(chan struct{})
+	defer close(stall)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stall" {
+			select {
+			case <-stall:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Write(make([]byte, 1024))
+	}))
+	defer server.Close()
+
+	registry := &remote.Registry{URL: server.URL + "/stall", Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	if _, err := registry.Load(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("a stalled fetch must time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the timeout must bound the fetch, took %v", elapsed)
+	}
+	if registry.Err() != nil {
+		t.Errorf("a fetch that timed out must not be cached, got %v", registry.Err())
+	}
+
+	registry = &remote.Registry{URL: server.URL, MaxBodySize: 100}
+	if _, err := registry.Load(context.Background()); !errors.Is(err, remote.ErrBodyTooLarge) {
+		t.Errorf("an oversized body must be rejected, got %v", err)
+	}
+}
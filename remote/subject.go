@@ -0,0 +1,367 @@
+package remote
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var _ protoiter.Files = (*SubjectRegistry)(nil)
+
+// SubjectRegistry pulls protobuf schemas lazily from a Confluent-style schema registry and exposes the resolved files.
+//
+// A file is fetched the first time it is looked up with [SubjectRegistry.LoadFile] or FindFileByPath,
+// as the latest version of its subject, together with the files it references.
+// The schemas are requested in the serialized format, so each is a FileDescriptorProto and needs no compiler.
+// RangeFiles and RangeFilesByPackage need every file, so they list the subjects of the registry and fetch them all.
+// Fetched files are cached until [SubjectRegistry.Reset], and so are failures, except those caused by
+// the context or the timeout of the fetch.
+//
+// The zero value is not usable; URL must be set.
+// A SubjectRegistry is safe for concurrent use.
+type SubjectRegistry struct {
+	// URL is the base URL of the registry API, e.g. "http://localhost:8081".
+	URL string
+
+	// Client is the HTTP client used for the requests.
+	// If nil, http.DefaultClient is used.
+	Client *http.Client
+
+	// Timeout bounds each request, including reading the body, in addition to any deadline of its context
+	// and any timeout of Client. If zero, DefaultTimeout is used; if negative, the requests are not bounded.
+	Timeout time.Duration
+
+	// MaxBodySize is the largest response body accepted, in bytes.
+	// If zero or negative, DefaultMaxBodySize is used.
+	MaxBodySize int64
+
+	// Subject returns the subject holding the file at path, for files that are looked up or imported by path
+	// without a reference. If nil, the path itself is the subject, as the default reference subject naming
+	// of the Confluent serializers produces.
+	Subject func(path string) string
+
+	// Resolver resolves imports before they are looked up in the registry, typically well-known types.
+	// If nil, protoregistry.GlobalFiles is used.
+	Resolver protodesc.Resolver
+
+	mu     sync.Mutex
+	files  *protoregistry.Files
+	failed map[string]error // by path, or by subject for subjects fetched by RangeFiles
+	loaded map[string]bool  // subjects whose latest version is in files
+	listed bool
+	err    error
+}
+
+// schemaVersion is a version of a subject as returned by the registry.
+type schemaVersion struct {
+	Subject    string            `json:"subject"`
+	Version    int               `json:"version"`
+	SchemaType string            `json:"schemaType"`
+	Schema     string            `json:"schema"`
+	References []schemaReference `json:"references"`
+}
+
+// schemaReference is an import of a schema, resolved to a version of another subject.
+type schemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// LoadFile returns the file at path, fetching it and the files it references on first use.
+//
+// Parameters:
+//   - ctx: The context of the HTTP requests, used only if the file is not cached yet
+//   - path: The path of the file
+//
+// Returns:
+//   - The file descriptor, or the error of fetching or resolving it
+func (r *SubjectRegistry) LoadFile(ctx context.Context, path string) (protoreflect.FileDescriptor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load(ctx, path, r.subject(path), "latest", make(map[string]bool))
+}
+
+// FindFileByPath looks up a file by path, fetching it on first use; it implements [protodesc.Resolver].
+func (r *SubjectRegistry) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	return r.LoadFile(context.Background(), path)
+}
+
+// FindDescriptorByName looks up a descriptor among the files fetched so far; it implements [protodesc.Resolver].
+// Descriptors are not fetched by name, since the registry is not indexed by symbol.
+func (r *SubjectRegistry) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.files == nil {
+		return nil, protoregistry.NotFound
+	}
+	return r.files.FindDescriptorByName(name)
+}
+
+// Reset drops the cached files and failures so the next use fetches them again.
+func (r *SubjectRegistry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files, r.failed, r.loaded, r.listed, r.err = nil, nil, nil, false, nil
+}
+
+// Err returns the error of the last listing of the registry by RangeFiles, RangeFilesByPackage or EachFile,
+// or nil if it succeeded or has not happened.
+//
+// RangeFiles and RangeFilesByPackage cannot report errors; they iterate over the files that could be fetched,
+// and Err reports why others are missing.
+func (r *SubjectRegistry) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// EachFile creates a sequential iterator over the files of every subject in the registry.
+//
+// The subjects are listed and fetched on first use. If any of them fails, the files that could be fetched
+// are yielded first, then a nil descriptor with the error.
+//
+// Parameters:
+//   - ctx: The context of the HTTP requests, used only if the subjects are not cached yet
+//
+// Returns:
+//   - An iterator sequence that yields each file descriptor, or an error
+func (r *SubjectRegistry) EachFile(ctx context.Context) iter.Seq2[protoreflect.FileDescriptor, error] {
+	return func(yield func(protoreflect.FileDescriptor, error) bool) {
+		files, err := r.loadAll(ctx)
+		for _, fd := range files {
+			if !yield(fd, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// RangeFiles implements [protoiter.Files].
+func (r *SubjectRegistry) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	files, _ := r.loadAll(context.Background())
+	for _, fd := range files {
+		if !f(fd) {
+			return
+		}
+	}
+}
+
+// RangeFilesByPackage implements [protoiter.Files].
+func (r *SubjectRegistry) RangeFilesByPackage(name protoreflect.FullName, f func(protoreflect.FileDescriptor) bool) {
+	files, _ := r.loadAll(context.Background())
+	for _, fd := range files {
+		if fd.Package() == name && !f(fd) {
+			return
+		}
+	}
+}
+
+func (r *SubjectRegistry) subject(path string) string {
+	if r.Subject == nil {
+		return path
+	}
+	return r.Subject(path)
+}
+
+// loadAll fetches the latest version of every subject once, and returns a snapshot of the files fetched so far,
+// so that they can be iterated while other files are being fetched.
+func (r *SubjectRegistry) loadAll(ctx context.Context) ([]protoreflect.FileDescriptor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.listed {
+		err := r.list(ctx)
+		if err != nil && interrupted(ctx, err) {
+			return r.snapshot(), err
+		}
+		r.listed, r.err = true, err
+	}
+	return r.snapshot(), r.err
+}
+
+// snapshot returns the files fetched so far. r.mu must be held.
+func (r *SubjectRegistry) snapshot() []protoreflect.FileDescriptor {
+	var files []protoreflect.FileDescriptor
+	if r.files != nil {
+		r.files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			files = append(files, fd)
+			return true
+		})
+	}
+	return files
+}
+
+// list fetches the latest version of every subject that has not been fetched yet. r.mu must be held.
+func (r *SubjectRegistry) list(ctx context.Context) error {
+	_, body, err := get(ctx, r.Client, r.endpoint("subjects"), r.Timeout, r.MaxBodySize)
+	if err != nil {
+		return err
+	}
+	var subjects []string
+	if err := json.Unmarshal(body, &subjects); err != nil {
+		return fmt.Errorf("remote: decode subjects of %s: %w", r.URL, err)
+	}
+	var errs []error
+	for _, subject := range subjects {
+		if r.loaded[subject] {
+			continue
+		}
+		if _, err := r.load(ctx, "", subject, "latest", make(map[string]bool)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// load returns the file at path, fetching the given version of subject if it is not cached.
+// An empty path stands for the path recorded in the fetched schema.
+// loading holds the paths being loaded, to detect import cycles. r.mu must be held.
+func (r *SubjectRegistry) load(ctx context.Context, path, subject, version string, loading map[string]bool) (protoreflect.FileDescriptor, error) {
+	key := path
+	if key == "" {
+		key = subject
+	} else if r.files != nil {
+		if fd, err := r.files.FindFileByPath(path); err == nil {
+			return fd, nil
+		}
+	}
+	if err, ok := r.failed[key]; ok {
+		return nil, err
+	}
+	fd, err := r.fetch(ctx, path, subject, version, loading)
+	if err != nil {
+		if !interrupted(ctx, err) {
+			if r.failed == nil {
+				r.failed = make(map[string]error)
+			}
+			r.failed[key] = err
+		}
+		return nil, err
+	}
+	if version == "latest" {
+		if r.loaded == nil {
+			r.loaded = make(map[string]bool)
+		}
+		r.loaded[subject] = true
+	}
+	return fd, nil
+}
+
+func (r *SubjectRegistry) fetch(ctx context.Context, path, subject, version string, loading map[string]bool) (protoreflect.FileDescriptor, error) {
+	u := r.endpoint("subjects", subject, "versions", version) + "?format=serialized"
+	_, body, err := get(ctx, r.Client, u, r.Timeout, r.MaxBodySize)
+	if err != nil {
+		return nil, err
+	}
+	var sv schemaVersion
+	if err := json.Unmarshal(body, &sv); err != nil {
+		return nil, fmt.Errorf("remote: decode %s: %w", u, err)
+	}
+	// The registry omits the type of Avro schemas, its default.
+	if schemaType := cmp.Or(sv.SchemaType, "AVRO"); schemaType != "PROTOBUF" {
+		return nil, fmt.Errorf("remote: subject %q holds a %s schema, not a PROTOBUF one", subject, schemaType)
+	}
+	raw, err := base64.StdEncoding.DecodeString(sv.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("remote: decode %s: %w", u, err)
+	}
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := proto.Unmarshal(raw, fdp); err != nil {
+		return nil, fmt.Errorf("remote: decode %s: %w", u, err)
+	}
+	if path == "" {
+		path = cmp.Or(fdp.GetName(), subject)
+		if r.files != nil {
+			if fd, err := r.files.FindFileByPath(path); err == nil {
+				return fd, nil // already fetched through a reference
+			}
+		}
+	}
+	fdp.Name = proto.String(path)
+	if loading[path] {
+		return nil, fmt.Errorf("remote: import cycle through %q", path)
+	}
+	loading[path] = true
+	defer delete(loading, path)
+	for _, ref := range sv.References {
+		if _, err := r.load(ctx, ref.Name, ref.Subject, strconv.Itoa(ref.Version), loading); err != nil {
+			return nil, fmt.Errorf("remote: reference %q of %q: %w", ref.Name, path, err)
+		}
+	}
+	fd, err := protodesc.NewFile(fdp, importResolver{r, ctx, loading})
+	if err != nil {
+		return nil, fmt.Errorf("remote: resolve %q: %w", path, err)
+	}
+	if r.files == nil {
+		r.files = new(protoregistry.Files)
+	}
+	if err := r.files.RegisterFile(fd); err != nil {
+		return nil, fmt.Errorf("remote: register %q: %w", path, err)
+	}
+	return fd, nil
+}
+
+// endpoint returns the URL of the API path made of the escaped segments.
+func (r *SubjectRegistry) endpoint(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, s := range segments {
+		escaped[i] = url.PathEscape(s)
+	}
+	return strings.TrimSuffix(r.URL, "/") + "/" + strings.Join(escaped, "/")
+}
+
+// importResolver resolves the imports of a file being fetched: first among the fetched files,
+// then in the Resolver of the registry, and finally by fetching them from the registry.
+type importResolver struct {
+	r       *SubjectRegistry
+	ctx     context.Context
+	loading map[string]bool
+}
+
+func (i importResolver) resolver() protodesc.Resolver {
+	if i.r.Resolver == nil {
+		return protoregistry.GlobalFiles
+	}
+	return i.r.Resolver
+}
+
+func (i importResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if i.r.files != nil {
+		if fd, err := i.r.files.FindFileByPath(path); err == nil {
+			return fd, nil
+		}
+	}
+	if fd, err := i.resolver().FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return i.r.load(i.ctx, path, i.r.subject(path), "latest", i.loading)
+}
+
+func (i importResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if i.r.files != nil {
+		if d, err := i.r.files.FindDescriptorByName(name); err == nil {
+			return d, nil
+		}
+	}
+	return i.resolver().FindDescriptorByName(name)
+}
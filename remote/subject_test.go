@@ -0,0 +1,178 @@
+package remote_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/protoiter/remote"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// subjectServer serves a Confluent-style schema registry holding:
+//   - "event.proto": event.proto, importing common.proto through a reference to version 1 of "common"
+//     and the well-known google/protobuf/timestamp.proto without one
+//   - "common": common.proto
+//   - "clicks-value": an Avro schema
+//   - "stall": a schema that is never served
+type subjectServer struct {
+	*httptest.Server
+	stall chan struct{}
+
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+func newSubjectServer(t *testing.T) *subjectServer {
+	common := &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("common.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Common")}},
+	}
+	event := fileDescriptorSet().File[0]
+	event.Dependency = append(event.Dependency, "common.proto")
+	event.MessageType[0].Field = append(event.MessageType[0].Field, &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("common"),
+		Number:   proto.Int32(2),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+		TypeName: proto.String(".test.Common"),
+	})
+	serialized := func(fdp *descriptorpb.FileDescriptorProto) string {
+		b, _ := proto.Marshal(fdp)
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	versions := map[string]map[string]any{
+		"/subjects/event.proto/versions/latest": {
+			"subject": "event.proto", "version": 3, "schemaType": "PROTOBUF", "schema": serialized(event),
+			"references": []map[string]any{{"name": "common.proto", "subject": "common", "version": 1}},
+		},
+		"/subjects/common/versions/1":            {"subject": "common", "version": 1, "schemaType": "PROTOBUF", "schema": serialized(common)},
+		"/subjects/common/versions/latest":       {"subject": "common", "version": 1, "schemaType": "PROTOBUF", "schema": serialized(common)},
+		"/subjects/clicks-value/versions/latest": {"subject": "clicks-value", "version": 1, "schema": `{"type":"record","name":"Click","fields":[]}`},
+	}
+	s := &subjectServer{stall: make(chan struct{}), requests: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[r.URL.Path]++
+		s.mu.Unlock()
+		switch {
+		case r.URL.Path == "/subjects":
+			json.NewEncoder(w).Encode([]string{"event.proto", "common", "clicks-value"})
+		case strings.HasPrefix(r.URL.Path, "/subjects/stall/"):
+			select {
+			case <-s.stall:
+			case <-r.Context().Done():
+			}
+		case versions[r.URL.Path] != nil:
+			if r.URL.Query().Get("format") != "serialized" {
+				t.Errorf("%s: the serialized format must be requested", r.URL)
+			}
+			json.NewEncoder(w).Encode(versions[r.URL.Path])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(func() {
+		close(s.stall)
+		s.Close()
+	})
+	return s
+}
+
+// count returns the number of requests for path.
+func (s *subjectServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+func TestSubjectRegistry(t *testing.T) {
+	server := newSubjectServer(t)
+	registry := &remote.SubjectRegistry{URL: server.URL}
+
+	fd, err := registry.LoadFile(context.Background(), "event.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	field := fd.Messages().ByName("Event").Fields().ByName("common")
+	if field.Message().FullName() != "test.Common" || field.Message().ParentFile().Path() != "common.proto" {
+		t.Errorf("the reference must be resolved, got %v", field.Message().FullName())
+	}
+	if server.count("/subjects/common/versions/1") != 1 || server.count("/subjects") != 0 {
+		t.Error("only the file and its references must be fetched")
+	}
+	if _, err := registry.FindFileByPath("common.proto"); err != nil {
+		t.Error(err)
+	}
+	d, err := registry.FindDescriptorByName("test.Event")
+	if err != nil || d.(protoreflect.MessageDescriptor).Fields().Len() != 2 {
+		t.Errorf("unexpected descriptor %v, %v", d, err)
+	}
+	if server.count("/subjects/event.proto/versions/latest") != 1 || server.count("/subjects/common/versions/latest") != 0 {
+		t.Error("fetched files must be cached")
+	}
+
+	var got []string
+	var errs []error
+	for fd, err := range registry.EachFile(context.Background()) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		got = append(got, fd.Path())
+	}
+	slices.Sort(got)
+	if want := []string{"common.proto", "event.proto"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "AVRO") || registry.Err() == nil {
+		t.Errorf("the Avro subject must be reported, got %v", errs)
+	}
+	for range protoiter.EachFileByPackage(registry, "test") {
+	}
+	if server.count("/subjects") != 1 || server.count("/subjects/clicks-value/versions/latest") != 1 {
+		t.Error("the listing must be cached, including its failure")
+	}
+
+	registry.Reset()
+	for range protoiter.EachFile(registry) {
+	}
+	if server.count("/subjects") != 2 {
+		t.Error("must list again after Reset")
+	}
+}
+
+func TestSubjectRegistryError(t *testing.T) {
+	server := newSubjectServer(t)
+	registry := &remote.SubjectRegistry{URL: server.URL}
+	for range 2 {
+		if _, err := registry.LoadFile(context.Background(), "missing.proto"); err == nil {
+			t.Error("a missing subject must be an error")
+		}
+	}
+	if n := server.count("/subjects/missing.proto/versions/latest"); n != 1 {
+		t.Errorf("a failed fetch must be cached, got %d requests", n)
+	}
+
+	registry = &remote.SubjectRegistry{URL: server.URL, Timeout: 50 * time.Millisecond}
+	for range 2 {
+		if _, err := registry.LoadFile(context.Background(), "stall"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("a stalled fetch must time out, got %v", err)
+		}
+	}
+	if n := server.count("/subjects/stall/versions/latest"); n != 2 {
+		t.Errorf("a fetch that timed out must not be cached, got %d requests", n)
+	}
+}
@@ -0,0 +1,62 @@
+package protoiter
+
+import (
+	"iter"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachMissingRequired creates a sequential iterator over the required fields that are not set in a message.
+//
+// A field is required if it is declared as proto2 required or has the editions field_presence feature LEGACY_REQUIRED.
+// The iterator descends into every populated message value, including list elements and map values,
+// so a field missing from several nested messages is yielded once per occurrence.
+// Unlike [google.golang.org/protobuf/proto.CheckInitialized], it reports every missing field instead of stopping at the first.
+//
+// Parameters:
+//   - message: The protocol buffer message to validate
+//
+// Returns:
+//   - An iterator sequence that yields the descriptor of each missing required field
+func EachMissingRequired(message protoreflect.Message) iter.Seq[protoreflect.FieldDescriptor] {
+	return func(yield func(protoreflect.FieldDescriptor) bool) {
+		eachMissingRequired(message, yield)
+	}
+}
+
+func eachMissingRequired(message protoreflect.Message, yield func(protoreflect.FieldDescriptor) bool) bool {
+	fields := message.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if fd.Cardinality() == protoreflect.Required && !message.Has(fd) {
+			if !yield(fd) {
+				return false
+			}
+		}
+	}
+	ok := true
+	message.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+			v.Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+				ok = eachMissingRequired(v.Message(), yield)
+				return ok
+			})
+		case fd.IsList():
+			if fd.Message() == nil {
+				return true
+			}
+			list := v.List()
+			for i := 0; ok && i < list.Len(); i++ {
+				ok = eachMissingRequired(list.Get(i).Message(), yield)
+			}
+		case fd.Message() != nil:
+			ok = eachMissingRequired(v.Message(), yield)
+		}
+		return ok
+	})
+	return ok
+}
@@ -0,0 +1,85 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func ExampleEachMissingRequired() {
+	option := &descriptorpb.UninterpretedOption{
+		Name: []*descriptorpb.UninterpretedOption_NamePart{
+			{NamePart: proto.String("a")},
+			{IsExtension: proto.Bool(true)},
+		},
+	}
+	for field := range protoiter.EachMissingRequired(option.ProtoReflect()) {
+		fmt.Println(field.FullName())
+	}
+	// Output:
+	// google.protobuf.UninterpretedOption.NamePart.is_extension
+	// google.protobuf.UninterpretedOption.NamePart.name_part
+}
+
+func TestEachMissingRequired(t *testing.T) {
+	files := newFiles(`
+		name: "required.proto"
+		package: "test"
+		syntax: "editions"
+		edition: EDITION_2023
+		message_type {
+			name: "Outer"
+			field { name: "id" number: 1 type: TYPE_STRING options { features { field_presence: LEGACY_REQUIRED } } }
+			field { name: "inner" number: 2 type: TYPE_MESSAGE type_name: ".test.Inner" }
+			field { name: "items" number: 3 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".test.Inner" }
+			field { name: "byKey" number: 4 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".test.Outer.ByKeyEntry" }
+			nested_type {
+				name: "ByKeyEntry"
+				field { name: "key" number: 1 type: TYPE_STRING }
+				field { name: "value" number: 2 type: TYPE_MESSAGE type_name: ".test.Inner" }
+				options { map_entry: true }
+			}
+		}
+		message_type {
+			name: "Inner"
+			field { name: "name" number: 1 type: TYPE_STRING options { features { field_presence: LEGACY_REQUIRED } } }
+		}
+	`)
+	md := results.Must1(files.FindDescriptorByName("test.Outer")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	fields := md.Fields()
+	m.Mutable(fields.ByName("inner"))
+	items := m.Mutable(fields.ByName("items")).List()
+	items.Append(items.NewElement())
+	items.Append(items.NewElement())
+	byKey := m.Mutable(fields.ByName("byKey")).Map()
+	byKey.Mutable(protoreflect.ValueOfString("k").MapKey())
+
+	var got []protoreflect.FullName
+	for field := range protoiter.EachMissingRequired(m) {
+		got = append(got, field.FullName())
+	}
+	slices.Sort(got)
+	want := []protoreflect.FullName{"test.Inner.name", "test.Inner.name", "test.Inner.name", "test.Inner.name", "test.Outer.id"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	n := 0
+	for range protoiter.EachMissingRequired(m) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("must stop after break, got %d", n)
+	}
+}
@@ -0,0 +1,55 @@
+package protoiter
+
+import (
+	"fmt"
+	"iter"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachReservedViolation creates a sequential iterator over the fields of a new version of a message
+// that reuse a number or name the old version reserved or used for a different field.
+//
+// A field of new is reported if, in old,
+//   - its number is in a reserved range,
+//   - its name is reserved,
+//   - its number belongs to a field with a different name, or
+//   - its name belongs to a field with a different number.
+//
+// Each field is reported once, with a description of the first of these violations.
+// Fields of nested messages are not checked; call it for each pair of nested messages as needed.
+//
+// Parameters:
+//   - old: The message descriptor of the previous version
+//   - new: The message descriptor of the current version
+//
+// Returns:
+//   - An iterator sequence that yields each violating field of new and a description of the violation, in declaration order
+func EachReservedViolation(old, new protoreflect.MessageDescriptor) iter.Seq2[protoreflect.FieldDescriptor, string] {
+	return func(yield func(protoreflect.FieldDescriptor, string) bool) {
+		fields := new.Fields()
+		for i := range fields.Len() {
+			fd := fields.Get(i)
+			if reason := reservedViolation(old, fd); reason != "" && !yield(fd, reason) {
+				return
+			}
+		}
+	}
+}
+
+// reservedViolation describes how fd conflicts with the reservations and fields of old, or returns "".
+func reservedViolation(old protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor) string {
+	switch {
+	case old.ReservedRanges().Has(fd.Number()):
+		return fmt.Sprintf("number %d is reserved", fd.Number())
+	case old.ReservedNames().Has(fd.Name()):
+		return fmt.Sprintf("name %q is reserved", fd.Name())
+	}
+	if prev := old.Fields().ByNumber(fd.Number()); prev != nil && prev.Name() != fd.Name() {
+		return fmt.Sprintf("number %d was used by field %q", fd.Number(), prev.Name())
+	}
+	if prev := old.Fields().ByName(fd.Name()); prev != nil && prev.Number() != fd.Number() {
+		return fmt.Sprintf("name %q was used by field number %d", fd.Name(), prev.Number())
+	}
+	return ""
+}
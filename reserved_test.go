@@ -0,0 +1,72 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const oldUserProto = `
+	name: "user.proto"
+	package: "v1"
+	message_type {
+		name: "User"
+		field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "email" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "phone" number: 5 label: LABEL_OPTIONAL type: TYPE_STRING }
+		reserved_range { start: 3 end: 5 }
+		reserved_name: "password"
+	}
+`
+
+const newUserProto = `
+	name: "user.proto"
+	package: "v2"
+	message_type {
+		name: "User"
+		field { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "mail" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "age" number: 4 label: LABEL_OPTIONAL type: TYPE_INT32 }
+		field { name: "password" number: 6 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "phone" number: 7 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "nickname" number: 8 label: LABEL_OPTIONAL type: TYPE_STRING }
+	}
+`
+
+func userDescriptors() (old, new protoreflect.MessageDescriptor) {
+	old = results.Must1(newFiles(oldUserProto).FindDescriptorByName("v1.User")).(protoreflect.MessageDescriptor)
+	new = results.Must1(newFiles(newUserProto).FindDescriptorByName("v2.User")).(protoreflect.MessageDescriptor)
+	return old, new
+}
+
+func ExampleEachReservedViolation() {
+	for fd, reason := range protoiter.EachReservedViolation(userDescriptors()) {
+		fmt.Printf("%s: %s\n", fd.Name(), reason)
+	}
+	// Output:
+	// mail: number 2 was used by field "email"
+	// age: number 4 is reserved
+	// password: name "password" is reserved
+	// phone: name "phone" was used by field number 5
+}
+
+func TestEachReservedViolation(t *testing.T) {
+	old, new := userDescriptors()
+	for fd := range protoiter.EachReservedViolation(old, old) {
+		t.Errorf("a message must not violate itself: %s", fd.Name())
+	}
+	var got []protoreflect.Name
+	for fd := range protoiter.EachReservedViolation(old, new) {
+		got = append(got, fd.Name())
+		if len(got) == 2 {
+			break
+		}
+	}
+	if want := []protoreflect.Name{"mail", "age"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+}
@@ -0,0 +1,199 @@
+package protoiter
+
+import (
+	"iter"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EachFieldOfKind creates a sequential iterator over the fields of a specific kind declared in any message of a registry.
+//
+// Nested messages are included, so the key and value of a map field are reported with the synthetic map entry message.
+// Extension fields are not included.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - kind: The field kind to search for, e.g. [protoreflect.BytesKind]
+//
+// Returns:
+//   - An iterator sequence that yields each matching field with the message declaring it
+func EachFieldOfKind(files Files, kind protoreflect.Kind) iter.Seq2[protoreflect.MessageDescriptor, protoreflect.FieldDescriptor] {
+	return func(yield func(protoreflect.MessageDescriptor, protoreflect.FieldDescriptor) bool) {
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			md, ok := d.(protoreflect.MessageDescriptor)
+			if !ok {
+				return true
+			}
+			fields := md.Fields()
+			for i := range fields.Len() {
+				if fd := fields.Get(i); fd.Kind() == kind && !yield(md, fd) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// EachReferencing creates a sequential iterator over the fields in a registry whose type is a specific message or enum.
+//
+// Both message fields and extension fields are included.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - name: The full name of the referenced message or enum type
+//
+// Returns:
+//   - An iterator sequence that yields each field referencing the type
+func EachReferencing(files Files, name protoreflect.FullName) iter.Seq[protoreflect.FieldDescriptor] {
+	return func(yield func(protoreflect.FieldDescriptor) bool) {
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			fd, ok := d.(protoreflect.FieldDescriptor)
+			if !ok || fieldTypeName(fd) != name {
+				return true
+			}
+			return yield(fd)
+		})
+	}
+}
+
+// EachSymbol creates a sequential iterator over the named declarations in a registry, keyed by full name.
+//
+// Every descriptor declared in a file is yielded: messages, fields, oneofs, enums, enum values, extensions,
+// services and methods. The files themselves are not. Note that enum values are named in the scope enclosing their enum.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields the full name and descriptor of each declaration
+func EachSymbol(files Files) iter.Seq2[protoreflect.FullName, protoreflect.Descriptor] {
+	return func(yield func(protoreflect.FullName, protoreflect.Descriptor) bool) {
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			if _, ok := d.(protoreflect.FileDescriptor); ok {
+				return true
+			}
+			return yield(d.FullName(), d)
+		})
+	}
+}
+
+// fieldTypeName returns the full name of the message or enum type of a field, or "" for scalar fields.
+func fieldTypeName(fd protoreflect.FieldDescriptor) protoreflect.FullName {
+	switch {
+	case fd.Message() != nil:
+		return fd.Message().FullName()
+	case fd.Enum() != nil:
+		return fd.Enum().FullName()
+	}
+	return ""
+}
+
+// EachEnumUsage creates a sequential iterator over the usages of an enum in a registry.
+//
+// It yields every field (including extension fields) whose type is the enum,
+// and every method whose request or response message contains the enum,
+// directly or through nested message fields at any depth.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - enum: The full name of the enum
+//
+// Returns:
+//   - An iterator sequence that yields each [protoreflect.FieldDescriptor] and [protoreflect.MethodDescriptor] using the enum
+func EachEnumUsage(files Files, enum protoreflect.FullName) iter.Seq[protoreflect.Descriptor] {
+	return func(yield func(protoreflect.Descriptor) bool) {
+		contains := containsType(enum)
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			switch d := d.(type) {
+			case protoreflect.FieldDescriptor:
+				if d.Enum() != nil && d.Enum().FullName() == enum {
+					return yield(d)
+				}
+			case protoreflect.MethodDescriptor:
+				if contains(d.Input()) || contains(d.Output()) {
+					return yield(d)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// containsType returns a function reporting whether a message has a field of the named type,
+// directly or through message fields at any depth. Results are cached across calls.
+func containsType(name protoreflect.FullName) func(protoreflect.MessageDescriptor) bool {
+	known := make(map[protoreflect.FullName]bool)
+	var search func(md protoreflect.MessageDescriptor, visited map[protoreflect.FullName]bool) bool
+	search = func(md protoreflect.MessageDescriptor, visited map[protoreflect.FullName]bool) bool {
+		if found, ok := known[md.FullName()]; ok {
+			return found
+		}
+		if visited[md.FullName()] {
+			return false
+		}
+		visited[md.FullName()] = true
+		fields := md.Fields()
+		for i := range fields.Len() {
+			fd := fields.Get(i)
+			if fieldTypeName(fd) == name || (fd.Message() != nil && search(fd.Message(), visited)) {
+				known[md.FullName()] = true
+				return true
+			}
+		}
+		return false
+	}
+	return func(md protoreflect.MessageDescriptor) bool {
+		visited := make(map[protoreflect.FullName]bool)
+		found := search(md, visited)
+		if !found {
+			// A completed search that found nothing proves it for every message it visited.
+			for name := range visited {
+				known[name] = false
+			}
+		}
+		return found
+	}
+}
+
+// EachUnreferenced creates a sequential iterator over the messages and enums in a registry that nothing refers to.
+//
+// A type is referenced if it is the type of a field or extension, the extendee of an extension,
+// or the request or response of a method. A field of a message referring to the message itself does not count,
+// so self-recursive types that are otherwise unused are reported as well.
+// Because the registry is scanned before the first element is yielded, the iteration is not lazy.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields each unreferenced [protoreflect.MessageDescriptor] and [protoreflect.EnumDescriptor]
+func EachUnreferenced(files Files) iter.Seq[protoreflect.Descriptor] {
+	return func(yield func(protoreflect.Descriptor) bool) {
+		referenced := make(map[protoreflect.FullName]bool)
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			switch d := d.(type) {
+			case protoreflect.FieldDescriptor:
+				if name := fieldTypeName(d); name != "" && name != d.Parent().FullName() {
+					referenced[name] = true
+				}
+				if d.IsExtension() {
+					referenced[d.ContainingMessage().FullName()] = true
+				}
+			case protoreflect.MethodDescriptor:
+				referenced[d.Input().FullName()] = true
+				referenced[d.Output().FullName()] = true
+			}
+			return true
+		})
+		walkFiles(files, func(d protoreflect.Descriptor) bool {
+			switch d.(type) {
+			case protoreflect.MessageDescriptor, protoreflect.EnumDescriptor:
+				if !referenced[d.FullName()] {
+					return yield(d)
+				}
+			}
+			return true
+		})
+	}
+}
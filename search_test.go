@@ -0,0 +1,195 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func ExampleEachFieldOfKind() {
+	files := newFiles(acmeProto)
+	for message, field := range protoiter.EachFieldOfKind(files, protoreflect.BytesKind) {
+		fmt.Println(message.Name(), field.Name())
+	}
+	// Output:
+	// Blob data
+	// Blob parts
+	// Meta digest
+}
+
+func TestEachFieldOfKind(t *testing.T) {
+	files := newFiles(acmeProto)
+	var got []protoreflect.FullName
+	for message, field := range protoiter.EachFieldOfKind(files, protoreflect.EnumKind) {
+		if field.Parent() != message {
+			t.Errorf("%v must be declared in %v", field.FullName(), message.FullName())
+		}
+		got = append(got, field.FullName())
+	}
+	if want := []protoreflect.FullName{"acme.store.Blob.Meta.state"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	for range protoiter.EachFieldOfKind(files, protoreflect.BytesKind) {
+		break
+	}
+}
+
+func ExampleEachReferencing() {
+	files := newFiles(acmeProto)
+	for field := range protoiter.EachReferencing(files, "acme.store.State") {
+		fmt.Println(field.FullName())
+	}
+	// Output:
+	// acme.store.Blob.Meta.state
+}
+
+func TestEachReferencing(t *testing.T) {
+	files := newFiles(acmeProto, `
+		name: "acme/ext.proto"
+		package: "acme.ext"
+		dependency: "acme/store.proto"
+		message_type {
+			name: "Holder"
+			field { name: "blob" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".acme.store.Blob" }
+			field { name: "blobs" number: 2 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".acme.store.Blob" }
+			extension_range { start: 100 end: 200 }
+		}
+		extension {
+			name: "extra" number: 100 label: LABEL_OPTIONAL type: TYPE_MESSAGE
+			type_name: ".acme.store.Blob" extendee: ".acme.ext.Holder"
+		}
+	`)
+	var got []protoreflect.FullName
+	for field := range protoiter.EachReferencing(files, "acme.store.Blob") {
+		got = append(got, field.FullName())
+	}
+	slices.Sort(got)
+	want := []protoreflect.FullName{"acme.ext.Holder.blob", "acme.ext.Holder.blobs", "acme.ext.extra"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+}
+
+func ExampleEachEnumUsage() {
+	files := newFiles(acmeProto)
+	for d := range protoiter.EachEnumUsage(files, "acme.store.State") {
+		fmt.Println(d.FullName())
+	}
+	// Output:
+	// acme.store.Blob.Meta.state
+}
+
+func TestEachEnumUsage(t *testing.T) {
+	files := newFiles(`
+		name: "usage.proto"
+		package: "test"
+		message_type {
+			name: "Node"
+			field { name: "next" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.Node" }
+			field { name: "leaf" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.Leaf" }
+		}
+		message_type {
+			name: "Leaf"
+			field { name: "color" number: 1 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".test.Color" }
+		}
+		message_type {
+			name: "Loop"
+			field { name: "self" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.Loop" }
+		}
+		enum_type { name: "Color" value { name: "COLOR_UNSPECIFIED" number: 0 } }
+		service {
+			name: "Graph"
+			method { name: "Walk" input_type: ".test.Loop" output_type: ".test.Node" }
+			method { name: "Spin" input_type: ".test.Loop" output_type: ".test.Loop" }
+		}
+	`)
+	var got []protoreflect.FullName
+	for d := range protoiter.EachEnumUsage(files, "test.Color") {
+		got = append(got, d.FullName())
+	}
+	want := []protoreflect.FullName{"test.Leaf.color", "test.Graph.Walk"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+}
+
+func ExampleEachUnreferenced() {
+	files := newFiles(acmeProto)
+	for d := range protoiter.EachUnreferenced(files) {
+		fmt.Println(d.FullName())
+	}
+	// Output:
+	// acme.store.Blob.Meta
+}
+
+func TestEachUnreferenced(t *testing.T) {
+	var _ emptypb.Empty
+	files := newFiles(`
+		name: "unreferenced.proto"
+		package: "test"
+		message_type {
+			name: "Used"
+			field { name: "tags" number: 1 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".test.Used.TagsEntry" }
+			nested_type {
+				name: "TagsEntry"
+				field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+				field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_ENUM type_name: ".test.Kind" }
+				options { map_entry: true }
+			}
+			extension_range { start: 100 end: 200 }
+		}
+		message_type {
+			name: "Dead"
+			field { name: "self" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.Dead" }
+		}
+		message_type { name: "Request" }
+		enum_type { name: "Kind" value { name: "KIND_UNSPECIFIED" number: 0 } }
+		enum_type { name: "Unused" value { name: "UNUSED_UNSPECIFIED" number: 0 } }
+		extension {
+			name: "note" number: 100 label: LABEL_OPTIONAL type: TYPE_STRING extendee: ".test.Used"
+		}
+		service {
+			name: "S"
+			method { name: "M" input_type: ".test.Request" output_type: ".google.protobuf.Empty" }
+		}
+		dependency: "google/protobuf/empty.proto"
+	`)
+	var got []protoreflect.FullName
+	for d := range protoiter.EachUnreferenced(files) {
+		got = append(got, d.FullName())
+	}
+	want := []protoreflect.FullName{"test.Dead", "test.Unused"}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+}
+
+func ExampleEachSymbol() {
+	for name, d := range protoiter.EachSymbol(newFiles(acmeProto)) {
+		if _, ok := d.(protoreflect.EnumValueDescriptor); ok {
+			fmt.Println(name)
+		}
+	}
+	// Output:
+	// acme.store.STATE_UNSPECIFIED
+	// acme.store.STATE_READY
+}
+
+func TestEachSymbol(t *testing.T) {
+	files := newFiles(acmeProto, extProto)
+	n := 0
+	for name, d := range protoiter.EachSymbol(files) {
+		n++
+		if found := results.Must1(files.FindDescriptorByName(name)); found != d {
+			t.Errorf("%s: found %v", name, found.FullName())
+		}
+	}
+	if n != 15 {
+		t.Errorf("got %d symbols", n)
+	}
+}
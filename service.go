@@ -0,0 +1,102 @@
+package protoiter
+
+import (
+	"iter"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// MethodIO holds the request and response types of a method.
+//
+// It is an alias of an unnamed struct type, so code that spells out the struct type interoperates with it.
+type MethodIO = struct {
+	In, Out protoreflect.MessageDescriptor
+}
+
+// EachMethodIO creates a sequential iterator over the methods of a service together with their request and response types.
+//
+// Methods are yielded in declaration order.
+//
+// Parameters:
+//   - sd: The service descriptor whose methods to iterate
+//
+// Returns:
+//   - An iterator sequence that yields each method descriptor and its input and output message descriptors
+func EachMethodIO(sd protoreflect.ServiceDescriptor) iter.Seq2[protoreflect.MethodDescriptor, MethodIO] {
+	return func(yield func(protoreflect.MethodDescriptor, MethodIO) bool) {
+		methods := sd.Methods()
+		for i := range methods.Len() {
+			md := methods.Get(i)
+			if !yield(md, MethodIO{md.Input(), md.Output()}) {
+				return
+			}
+		}
+	}
+}
+
+// EachService creates a sequential iterator over the services declared in all files of a registry.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields each service descriptor, in declaration order within each file
+func EachService(files Files) iter.Seq[protoreflect.ServiceDescriptor] {
+	return func(yield func(protoreflect.ServiceDescriptor) bool) {
+		for fd := range EachFile(files) {
+			services := fd.Services()
+			for i := range services.Len() {
+				if !yield(services.Get(i)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// EachServiceByPackage creates a sequential iterator over the services declared in the files of a package.
+//
+// Only the files of the package are visited, through [Files.RangeFilesByPackage]; subpackages are not included.
+// Services are yielded in declaration order within each file.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - pkg: The package name, e.g. "acme.store"
+//
+// Returns:
+//   - An iterator sequence that yields each service descriptor of the package
+func EachServiceByPackage(files Files, pkg protoreflect.FullName) iter.Seq[protoreflect.ServiceDescriptor] {
+	return func(yield func(protoreflect.ServiceDescriptor) bool) {
+		for fd := range EachFileByPackage(files, pkg) {
+			services := fd.Services()
+			for i := range services.Len() {
+				if !yield(services.Get(i)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// EachMethodByPackage creates a sequential iterator over the methods of the services declared in the files of a package.
+//
+// It is like [EachServiceByPackage] but yields every method of each service, in declaration order.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - pkg: The package name, e.g. "acme.store"
+//
+// Returns:
+//   - An iterator sequence that yields each service descriptor and one of its method descriptors
+func EachMethodByPackage(files Files, pkg protoreflect.FullName) iter.Seq2[protoreflect.ServiceDescriptor, protoreflect.MethodDescriptor] {
+	return func(yield func(protoreflect.ServiceDescriptor, protoreflect.MethodDescriptor) bool) {
+		for sd := range EachServiceByPackage(files, pkg) {
+			methods := sd.Methods()
+			for i := range methods.Len() {
+				if !yield(sd, methods.Get(i)) {
+					return
+				}
+			}
+		}
+	}
+}
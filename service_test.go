@@ -0,0 +1,94 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const serviceProto = `
+	name: "service.proto"
+	package: "test"
+	dependency: ["google/protobuf/empty.proto", "ext.proto"]
+	service {
+		name: "Echo"
+		method { name: "Ping" input_type: ".google.protobuf.Empty" output_type: ".google.protobuf.Empty" }
+		method { name: "Send" input_type: ".test.Base" output_type: ".google.protobuf.Empty" client_streaming: true }
+	}
+`
+
+func ExampleEachMethodIO() {
+	var _ emptypb.Empty
+	sd := results.Must1(newFiles(extProto, serviceProto).FindDescriptorByName("test.Echo")).(protoreflect.ServiceDescriptor)
+	for md, io := range protoiter.EachMethodIO(sd) {
+		fmt.Println(md.Name(), io.In.FullName(), io.Out.FullName())
+	}
+	// Output:
+	// Ping google.protobuf.Empty google.protobuf.Empty
+	// Send test.Base google.protobuf.Empty
+}
+
+func TestEachMethodIO(t *testing.T) {
+	sd := results.Must1(newFiles(extProto, serviceProto).FindDescriptorByName("test.Echo")).(protoreflect.ServiceDescriptor)
+	n := 0
+	for md, io := range protoiter.EachMethodIO(sd) {
+		n++
+		if io.In != md.Input() || io.Out != md.Output() {
+			t.Errorf("%s: mismatched descriptors", md.FullName())
+		}
+		break
+	}
+	if n != 1 {
+		t.Errorf("iteration must stop after break")
+	}
+}
+
+func ExampleEachMethodByPackage() {
+	files := newFiles(extProto, serviceProto, acmeProto)
+	for sd, md := range protoiter.EachMethodByPackage(files, "test") {
+		fmt.Println(sd.Name(), md.Name())
+	}
+	// Output:
+	// Echo Ping
+	// Echo Send
+}
+
+func TestEachServiceByPackage(t *testing.T) {
+	files := newFiles(extProto, serviceProto, acmeProto)
+	for pkg, want := range map[protoreflect.FullName][]protoreflect.FullName{
+		"test":       {"test.Echo"},
+		"acme.store": {"acme.store.BlobService"},
+		"acme":       nil,
+	} {
+		var got []protoreflect.FullName
+		for sd := range protoiter.EachServiceByPackage(files, pkg) {
+			got = append(got, sd.FullName())
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: must be equal\ngot\t%v\nwant\t%v", pkg, got, want)
+		}
+	}
+	for range protoiter.EachMethodByPackage(files, "test") {
+		break
+	}
+}
+
+func TestEachService(t *testing.T) {
+	files := newFiles(extProto, serviceProto, acmeProto)
+	var got []protoreflect.FullName
+	for sd := range protoiter.EachService(files) {
+		got = append(got, sd.FullName())
+	}
+	slices.Sort(got)
+	if want := []protoreflect.FullName{"acme.store.BlobService", "test.Echo"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+	for range protoiter.EachService(files) {
+		break
+	}
+}
@@ -0,0 +1,162 @@
+package protoiter
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/goaux/protoiter/internal/reflectutil"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SizeTree creates a sequential iterator over the encoded size attributable to every node of a message tree.
+//
+// The nodes are visited depth-first, with the fields of each message in field-number order:
+//   - the root message, with the size reported by [proto.Size]
+//   - every populated field, with the size of all its encoded elements including tags
+//   - every element of a repeated field, including its tag unless the field is packed
+//   - every entry of a map field, including its tag and length prefix
+//   - and recursively the fields of every nested message
+//
+// The sizes of the fields of a message add up to the size of the message minus its unknown fields.
+// Each yielded path is a new slice that the caller may retain.
+//
+// Parameters:
+//   - m: The message to analyze
+//
+// Returns:
+//   - An iterator sequence that yields the path of each node and its encoded size in bytes
+func SizeTree(m proto.Message) iter.Seq2[protopath.Path, int] {
+	return func(yield func(protopath.Path, int) bool) {
+		message := m.ProtoReflect()
+		path := protopath.Path{protopath.Root(message.Descriptor())}
+		if yield(path, proto.Size(m)) {
+			sizeTree(path, message, yield)
+		}
+	}
+}
+
+func sizeTree(path protopath.Path, message protoreflect.Message, yield func(protopath.Path, int) bool) bool {
+	for fd, v := range EachFieldInOrder(message, numberOrder) {
+		fieldPath := appendStep(path, protopath.FieldAccess(fd))
+		if !yield(fieldPath, fieldSize(fd, v)) {
+			return false
+		}
+		switch {
+		case fd.IsMap():
+			entries := v.Map()
+			keys := make([]protoreflect.MapKey, 0, entries.Len())
+			entries.Range(func(k protoreflect.MapKey, _ protoreflect.Value) bool {
+				keys = append(keys, k)
+				return true
+			})
+			slices.SortFunc(keys, reflectutil.CompareMapKeys)
+			for _, k := range keys {
+				entryPath := appendStep(fieldPath, protopath.MapIndex(k))
+				value := entries.Get(k)
+				if !yield(entryPath, mapEntrySize(fd, k, value)) {
+					return false
+				}
+				if fd.MapValue().Message() != nil && !sizeTree(entryPath, value.Message(), yield) {
+					return false
+				}
+			}
+		case fd.IsList():
+			list := v.List()
+			for i := range list.Len() {
+				elemPath := appendStep(fieldPath, protopath.ListIndex(i))
+				size := valueSize(fd, list.Get(i))
+				if !fd.IsPacked() {
+					size += tagSize(fd)
+				}
+				if !yield(elemPath, size) {
+					return false
+				}
+				if fd.Message() != nil && !sizeTree(elemPath, list.Get(i).Message(), yield) {
+					return false
+				}
+			}
+		case fd.Message() != nil:
+			if !sizeTree(fieldPath, v.Message(), yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// appendStep returns a new path with step appended, never sharing the backing array of path.
+func appendStep(path protopath.Path, step protopath.Step) protopath.Path {
+	return append(path[:len(path):len(path)], step)
+}
+
+func numberOrder(x, y protoreflect.FieldDescriptor) bool {
+	return x.Number() < y.Number()
+}
+
+// fieldSize returns the encoded size of a populated field, including all tags.
+func fieldSize(fd protoreflect.FieldDescriptor, v protoreflect.Value) int {
+	switch {
+	case fd.IsMap():
+		n := 0
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			n += mapEntrySize(fd, k, v)
+			return true
+		})
+		return n
+	case fd.IsList():
+		list := v.List()
+		n := 0
+		for i := range list.Len() {
+			n += valueSize(fd, list.Get(i))
+		}
+		if fd.IsPacked() {
+			return tagSize(fd) + protowire.SizeBytes(n)
+		}
+		return n + list.Len()*tagSize(fd)
+	}
+	return tagSize(fd) + valueSize(fd, v)
+}
+
+// mapEntrySize returns the encoded size of a map entry, including its tag and length prefix.
+func mapEntrySize(fd protoreflect.FieldDescriptor, k protoreflect.MapKey, v protoreflect.Value) int {
+	kd, vd := fd.MapKey(), fd.MapValue()
+	n := tagSize(kd) + valueSize(kd, k.Value()) + tagSize(vd) + valueSize(vd, v)
+	return tagSize(fd) + protowire.SizeBytes(n)
+}
+
+func tagSize(fd protoreflect.FieldDescriptor) int {
+	return protowire.SizeTag(fd.Number())
+}
+
+// valueSize returns the encoded size of a single value of a field, excluding its tag.
+// Groups include their end tag.
+func valueSize(fd protoreflect.FieldDescriptor, v protoreflect.Value) int {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return 1
+	case protoreflect.EnumKind:
+		return protowire.SizeVarint(uint64(int64(v.Enum())))
+	case protoreflect.Int32Kind, protoreflect.Int64Kind:
+		return protowire.SizeVarint(uint64(v.Int()))
+	case protoreflect.Sint32Kind, protoreflect.Sint64Kind:
+		return protowire.SizeVarint(protowire.EncodeZigZag(v.Int()))
+	case protoreflect.Uint32Kind, protoreflect.Uint64Kind:
+		return protowire.SizeVarint(v.Uint())
+	case protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind, protoreflect.FloatKind:
+		return protowire.SizeFixed32()
+	case protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
+		return protowire.SizeFixed64()
+	case protoreflect.StringKind:
+		return protowire.SizeBytes(len(v.String()))
+	case protoreflect.BytesKind:
+		return protowire.SizeBytes(len(v.Bytes()))
+	case protoreflect.MessageKind:
+		return protowire.SizeBytes(proto.Size(v.Message().Interface()))
+	case protoreflect.GroupKind:
+		return proto.Size(v.Message().Interface()) + tagSize(fd)
+	}
+	return 0
+}
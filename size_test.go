@@ -0,0 +1,81 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleSizeTree() {
+	for path, size := range protoiter.SizeTree(durationpb.New(1500)) {
+		fmt.Println(path, size)
+	}
+	// Output:
+	// (google.protobuf.Duration) 3
+	// (google.protobuf.Duration).nanos 3
+}
+
+func TestSizeTree(t *testing.T) {
+	messages := []proto.Message{
+		&descriptorpb.SourceCodeInfo{Location: []*descriptorpb.SourceCodeInfo_Location{
+			{Path: []int32{4, 0, 2, 300}, Span: []int32{1, 2, 3}, LeadingComments: proto.String("hello")},
+			{Path: []int32{-1}},
+		}},
+		results.Must1(structpb.NewStruct(map[string]any{
+			"name": "x",
+			"list": []any{1, "two", map[string]any{"three": true}},
+		})),
+	}
+	for _, m := range messages {
+		sizes := make(map[string]int)
+		for path, size := range protoiter.SizeTree(m) {
+			sizes[path.String()] = size
+		}
+		root := protopath.Path{protopath.Root(m.ProtoReflect().Descriptor())}
+		if got := sizes[root.String()]; got != proto.Size(m) {
+			t.Errorf("root size %d must equal proto.Size %d", got, proto.Size(m))
+		}
+		// Each top-level field is as large as a message holding only that field.
+		for field, value := range protoiter.EachField(m.ProtoReflect()) {
+			only := m.ProtoReflect().New()
+			only.Set(field, value)
+			path := append(root, protopath.FieldAccess(field)).String()
+			if got, want := sizes[path], proto.Size(only.Interface()); got != want {
+				t.Errorf("%s: got %d want %d", path, got, want)
+			}
+		}
+	}
+
+	// Packed elements exclude the tag, unpacked message elements include tag and length prefix.
+	sizes := make(map[string]int)
+	for path, size := range protoiter.SizeTree(messages[0]) {
+		sizes[path.String()] = size
+	}
+	want := map[string]int{
+		"(google.protobuf.SourceCodeInfo).location[0]":                  1 + 1 + 7 + 5 + 7,
+		"(google.protobuf.SourceCodeInfo).location[0].path":             1 + 1 + 5,
+		"(google.protobuf.SourceCodeInfo).location[0].path[3]":          2,
+		"(google.protobuf.SourceCodeInfo).location[1].path[0]":          10,
+		"(google.protobuf.SourceCodeInfo).location[0].leading_comments": 1 + 1 + 5,
+	}
+	for path, want := range want {
+		if got := sizes[path]; got != want {
+			t.Errorf("%s: got %d want %d", path, got, want)
+		}
+	}
+
+	n := 0
+	for range protoiter.SizeTree(messages[1]) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package protoiter
+
+import (
+	"database/sql"
+	"fmt"
+	"iter"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// EachRow creates a sequential iterator over messages decoded from a bytes column of database rows.
+//
+// Each row is scanned, the value of the given column is unmarshaled with [proto.Unmarshal] into a message created by newM,
+// and the message is yielded. The other columns are discarded.
+// If a value cannot be unmarshaled, the message and the error are yielded and iteration continues with the next row;
+// scan and iteration errors are yielded with a zero message and end the iteration.
+// The rows are closed when the iteration stops, whether it is exhausted or the loop breaks.
+//
+// Parameters:
+//   - rows: The result set to read; it is consumed and closed
+//   - column: The zero-based index of the column holding the wire-format message
+//   - newM: A function returning a new empty message for each row
+//
+// Returns:
+//   - An iterator sequence that yields each decoded message, or an error
+func EachRow[M proto.Message](rows *sql.Rows, column int, newM func() M) iter.Seq2[M, error] {
+	return func(yield func(M, error) bool) {
+		defer rows.Close()
+		var zero M
+		columns, err := rows.Columns()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		if column < 0 || column >= len(columns) {
+			yield(zero, fmt.Errorf("protoiter: column %d out of range [0, %d)", column, len(columns)))
+			return
+		}
+		var data []byte
+		dest := make([]any, len(columns))
+		for i := range dest {
+			dest[i] = new(any)
+		}
+		dest[column] = &data
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				yield(zero, err)
+				return
+			}
+			m := newM()
+			if !yield(m, proto.Unmarshal(data, m)) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
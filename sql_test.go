@@ -0,0 +1,91 @@
+package protoiter_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// rowsDriver is a database driver serving a fixed table with an id and a data column.
+type rowsDriver [][]driver.Value
+
+func (d rowsDriver) Open(string) (driver.Conn, error) { return rowsConn{d}, nil }
+
+type rowsConn struct{ rows rowsDriver }
+
+func (c rowsConn) Prepare(string) (driver.Stmt, error) { return rowsStmt(c), nil }
+func (c rowsConn) Close() error                        { return nil }
+func (c rowsConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type rowsStmt rowsConn
+
+func (s rowsStmt) Close() error                               { return nil }
+func (s rowsStmt) NumInput() int                              { return 0 }
+func (s rowsStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s rowsStmt) Query([]driver.Value) (driver.Rows, error)  { return &fakeRows{rows: s.rows}, nil }
+
+type fakeRows struct {
+	rows rowsDriver
+}
+
+func (r *fakeRows) Columns() []string { return []string{"id", "data"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("protoiter-rows", rowsDriver{
+		{int64(1), results.Must1(proto.Marshal(&durationpb.Duration{Seconds: 1}))},
+		{int64(2), []byte{0xff}},
+		{int64(3), results.Must1(proto.Marshal(&durationpb.Duration{Seconds: 3}))},
+	})
+}
+
+func TestEachRow(t *testing.T) {
+	db := results.Must1(sql.Open("protoiter-rows", ""))
+	defer db.Close()
+	rows := results.Must1(db.QueryContext(context.Background(), "SELECT id, data FROM t"))
+	var got []int64
+	errs := 0
+	for m, err := range protoiter.EachRow(rows, 1, func() *durationpb.Duration { return new(durationpb.Duration) }) {
+		if err != nil {
+			errs++
+			continue
+		}
+		got = append(got, m.GetSeconds())
+	}
+	if errs != 1 || len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("unexpected result %v with %d errors", got, errs)
+	}
+	if rows.Next() {
+		t.Error("rows must be closed")
+	}
+
+	rows = results.Must1(db.QueryContext(context.Background(), "SELECT id, data FROM t"))
+	for range protoiter.EachRow(rows, 1, func() *durationpb.Duration { return new(durationpb.Duration) }) {
+		break
+	}
+	if err := rows.Err(); err != nil || rows.Next() {
+		t.Error("rows must be closed after break")
+	}
+
+	rows = results.Must1(db.QueryContext(context.Background(), "SELECT id, data FROM t"))
+	for _, err := range protoiter.EachRow(rows, 2, func() *durationpb.Duration { return new(durationpb.Duration) }) {
+		if err == nil {
+			t.Error("an invalid column must be an error")
+		}
+	}
+}
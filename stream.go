@@ -0,0 +1,321 @@
+package protoiter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"slices"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxMessageSize is the record size limit used when [DelimitedOptions.MaxMessageSize] is not positive.
+const DefaultMaxMessageSize = 64 << 20
+
+// ErrMessageTooLarge is wrapped by the error yielded for a record larger than [DelimitedOptions.MaxMessageSize].
+var ErrMessageTooLarge = errors.New("protoiter: message too large")
+
+// DelimitedOptions configures the iterators over varint length-delimited streams.
+//
+// The zero value reads records of up to [DefaultMaxMessageSize] bytes and stops at the first oversized one.
+type DelimitedOptions struct {
+	// MaxMessageSize is the largest record size accepted, in bytes.
+	// The length prefix is checked before anything is allocated, so a corrupted prefix
+	// cannot trigger a huge allocation. If zero or negative, DefaultMaxMessageSize is used.
+	MaxMessageSize int
+
+	// SkipOversized makes the iteration continue after an oversized record.
+	// The record is discarded from the stream, without being buffered, after its error is yielded.
+	SkipOversized bool
+
+	// Unmarshal is used to decode each record.
+	Unmarshal proto.UnmarshalOptions
+
+	// ZeroCopy makes [EachDelimitedRecord] yield records that alias its internal read buffer instead of copies.
+	// Such a record is only valid until the loop body returns and must not be modified.
+	// It does not affect the iterators that decode messages, which never retain the buffer.
+	ZeroCopy bool
+
+	// Pool enables message reuse when scanning many records.
+	// If non-nil, messages are taken from the pool, falling back to newM when it is empty or holds another type,
+	// and each message is reset and put back once the loop body has finished with it.
+	// The caller must not retain a yielded message, or anything referencing its fields, beyond the loop body.
+	// A pool typically creates messages with New, e.g. dynamicpb.NewMessage for a dynamic type.
+	Pool *sync.Pool
+}
+
+func (o DelimitedOptions) maxSize() int {
+	if o.MaxMessageSize <= 0 {
+		return DefaultMaxMessageSize
+	}
+	return o.MaxMessageSize
+}
+
+// EachDelimited creates a sequential iterator over messages read from a stream of varint length-delimited records,
+// as written by [google.golang.org/protobuf/encoding/protodelim].
+//
+// Each record is unmarshaled into a message created by newM.
+// If a record cannot be unmarshaled, the message and an error naming the record offset are yielded and iteration continues.
+// A record larger than the size limit yields a zero message and an error wrapping [ErrMessageTooLarge];
+// iteration then stops unless opts.SkipOversized is set.
+// Read errors, including a truncated record, are yielded with a zero message and end the iteration.
+//
+// Parameters:
+//   - r: The stream of length-delimited records
+//   - newM: A function returning a new empty message for each record
+//   - opts: The options controlling the size limit and decoding
+//
+// Returns:
+//   - An iterator sequence that yields each decoded message, or an error
+func EachDelimited[M proto.Message](r io.Reader, newM func() M, opts DelimitedOptions) iter.Seq2[M, error] {
+	return func(yield func(M, error) bool) {
+		eachDelimited(newFrameReader(r, 0), newM, opts, func(_ int64, m M, err error) bool {
+			return yield(m, err)
+		})
+	}
+}
+
+// EachDelimitedRecord creates a sequential iterator over the raw records of a stream of varint length-delimited records.
+//
+// Each record is yielded without its length prefix and without being decoded, which suits filtering, counting
+// or copying records between streams. Records are copies unless opts.ZeroCopy is set.
+// Oversized records and read errors are handled as by [EachDelimited]; opts.Unmarshal and opts.Pool are not used.
+//
+// Parameters:
+//   - r: The stream of length-delimited records
+//   - opts: The options controlling the size limit and copying
+//
+// Returns:
+//   - An iterator sequence that yields each record, or an error
+func EachDelimitedRecord(r io.Reader, opts DelimitedOptions) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		fr := newFrameReader(r, 0)
+		for {
+			_, record, err := fr.next(opts.maxSize())
+			switch {
+			case errors.Is(err, io.EOF):
+				return
+			case errors.Is(err, ErrMessageTooLarge):
+				if !yield(nil, err) || !opts.SkipOversized {
+					return
+				}
+				if err := fr.skip(); err != nil {
+					yield(nil, err)
+					return
+				}
+				continue
+			case err != nil:
+				yield(nil, err)
+				return
+			}
+			if !opts.ZeroCopy {
+				record = slices.Clone(record)
+			}
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+// eachDelimited decodes the records of fr and calls yield with the offset of each.
+func eachDelimited[M proto.Message](fr *frameReader, newM func() M, opts DelimitedOptions, yield func(int64, M, error) bool) {
+	var zero M
+	for {
+		offset, record, err := fr.next(opts.maxSize())
+		switch {
+		case errors.Is(err, io.EOF):
+			return
+		case errors.Is(err, ErrMessageTooLarge):
+			if !yield(offset, zero, err) || !opts.SkipOversized {
+				return
+			}
+			if err := fr.skip(); err != nil {
+				yield(fr.offset, zero, err)
+				return
+			}
+			continue
+		case err != nil:
+			yield(offset, zero, err)
+			return
+		}
+		m, ok := poolGet[M](opts.Pool)
+		if !ok {
+			m = newM()
+		}
+		if err = opts.Unmarshal.Unmarshal(record, m); err != nil {
+			err = fmt.Errorf("protoiter: record at offset %d: %w", offset, err)
+		}
+		ok = yield(offset, m, err)
+		if opts.Pool != nil {
+			proto.Reset(m)
+			opts.Pool.Put(m)
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// poolGet returns a message of type M from pool, if there is one.
+func poolGet[M proto.Message](pool *sync.Pool) (M, bool) {
+	if pool == nil {
+		var zero M
+		return zero, false
+	}
+	m, ok := pool.Get().(M)
+	return m, ok
+}
+
+// frameReader reads varint length-delimited records and tracks the byte offset in the stream.
+type frameReader struct {
+	r       *bufio.Reader
+	offset  int64  // offset of the next unread byte
+	pending uint64 // size of an oversized record not yet consumed
+	buf     []byte
+}
+
+func newFrameReader(r io.Reader, offset int64) *frameReader {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &frameReader{r: br, offset: offset}
+}
+
+// next reads the next record and returns its offset and contents, valid until the next call.
+// It returns io.EOF at the clean end of the stream, and an error wrapping ErrMessageTooLarge,
+// leaving the record unread, if the record exceeds max.
+func (fr *frameReader) next(max int) (int64, []byte, error) {
+	offset := fr.offset
+	n := 0
+	size, err := binary.ReadUvarint(byteCounter{fr.r, &n})
+	fr.offset += int64(n)
+	if err != nil {
+		if errors.Is(err, io.EOF) && n > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		if !errors.Is(err, io.EOF) {
+			err = fmt.Errorf("protoiter: record at offset %d: %w", offset, err)
+		}
+		return offset, nil, err
+	}
+	if size > uint64(max) {
+		fr.pending = size
+		return offset, nil, fmt.Errorf("protoiter: record at offset %d: size %d exceeds the limit %d: %w", offset, size, max, ErrMessageTooLarge)
+	}
+	fr.buf = slices.Grow(fr.buf[:0], int(size))[:size]
+	m, err := io.ReadFull(fr.r, fr.buf)
+	fr.offset += int64(m)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return offset, nil, fmt.Errorf("protoiter: record at offset %d: %w", offset, err)
+	}
+	return offset, fr.buf, nil
+}
+
+// skip discards the oversized record reported by the last call to next.
+func (fr *frameReader) skip() error {
+	n, err := io.CopyN(io.Discard, fr.r, int64(min(fr.pending, math.MaxInt64)))
+	fr.offset += n
+	fr.pending = 0
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return fmt.Errorf("protoiter: skipping oversized record: %w", err)
+	}
+	return nil
+}
+
+// byteCounter is an io.ByteReader that counts the bytes read.
+type byteCounter struct {
+	r *bufio.Reader
+	n *int
+}
+
+func (c byteCounter) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		*c.n++
+	}
+	return b, err
+}
+
+// EachDelimitedIndexed creates a sequential iterator over messages read from a seekable stream of varint length-delimited records,
+// keyed by the byte offset of each record.
+//
+// Offsets are absolute positions in r, starting from its current position, so consumers can build sparse indexes
+// over large files while reading them once. An oversized record is skipped if opts.SkipOversized is set;
+// any other error ends the iteration and is reported by the returned function, which returns nil after a clean end.
+// The position of r after the iteration is unspecified because reads are buffered.
+//
+// Parameters:
+//   - r: The stream of length-delimited records
+//   - newM: A function returning a new empty message for each record
+//   - opts: The options controlling the size limit and decoding
+//
+// Returns:
+//   - An iterator sequence that yields the offset and message of each record
+//   - A function returning the error that ended the last iteration, if any
+func EachDelimitedIndexed[M proto.Message](r io.ReadSeeker, newM func() M, opts DelimitedOptions) (iter.Seq2[int64, M], func() error) {
+	var lastErr error
+	seq := func(yield func(int64, M) bool) {
+		lastErr = nil
+		start, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			lastErr = err
+			return
+		}
+		eachDelimited(newFrameReader(r, start), newM, opts, func(offset int64, m M, err error) bool {
+			if err != nil {
+				if errors.Is(err, ErrMessageTooLarge) && opts.SkipOversized {
+					return true
+				}
+				lastErr = err
+				return false
+			}
+			return yield(offset, m)
+		})
+	}
+	return seq, func() error { return lastErr }
+}
+
+// ResumeAt is like [EachDelimitedIndexed] but starts reading at an absolute offset of r.
+//
+// A consumer that checkpoints the offset following the last processed record, or the offset of the first unprocessed one,
+// can restart exactly where it left off after a crash. The offset must be at a record boundary,
+// otherwise the records read are garbage or an error is reported.
+//
+// Parameters:
+//   - r: The stream of length-delimited records
+//   - offset: The absolute offset of the first record to read
+//   - newM: A function returning a new empty message for each record
+//   - opts: The options controlling the size limit and decoding
+//
+// Returns:
+//   - An iterator sequence that yields the offset and message of each record
+//   - A function returning the error that ended the last iteration, if any
+func ResumeAt[M proto.Message](r io.ReadSeeker, offset int64, newM func() M, opts DelimitedOptions) (iter.Seq2[int64, M], func() error) {
+	records, errFn := EachDelimitedIndexed(r, newM, opts)
+	var seekErr error
+	seq := func(yield func(int64, M) bool) {
+		if _, seekErr = r.Seek(offset, io.SeekStart); seekErr != nil {
+			return
+		}
+		records(yield)
+	}
+	return seq, func() error {
+		if seekErr != nil {
+			return seekErr
+		}
+		return errFn()
+	}
+}
@@ -0,0 +1,245 @@
+package protoiter_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// delimited encodes the messages as a varint length-delimited stream.
+func delimited(messages ...proto.Message) []byte {
+	var buf bytes.Buffer
+	for _, m := range messages {
+		if _, err := protodelim.MarshalTo(&buf, m); err != nil {
+			panic(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func ExampleEachDelimited() {
+	stream := delimited(durationpb.New(1), wrapperspb.String("a very long payload"), durationpb.New(3))
+	opts := protoiter.DelimitedOptions{MaxMessageSize: 16, SkipOversized: true}
+	for d, err := range protoiter.EachDelimited(bytes.NewReader(stream), newDuration, opts) {
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Println(d.AsDuration())
+	}
+	// Output:
+	// 1ns
+	// protoiter: record at offset 3: size 21 exceeds the limit 16: protoiter: message too large
+	// 3ns
+}
+
+func newDuration() *durationpb.Duration {
+	return new(durationpb.Duration)
+}
+
+func TestEachDelimited(t *testing.T) {
+	stream := delimited(durationpb.New(1), wrapperspb.String("a very long payload"), durationpb.New(3))
+
+	var errs []error
+	n := 0
+	for d, err := range protoiter.EachDelimited(bytes.NewReader(stream), newDuration, protoiter.DelimitedOptions{MaxMessageSize: 16}) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		n++
+		_ = d
+	}
+	if n != 1 || len(errs) != 1 || !errors.Is(errs[0], protoiter.ErrMessageTooLarge) {
+		t.Errorf("an oversized record must stop the iteration: %d messages, errors %v", n, errs)
+	}
+
+	// A corrupted length prefix claiming a huge record is rejected before allocating.
+	huge := protowire.AppendVarint(nil, 1<<40)
+	for _, err := range protoiter.EachDelimited(bytes.NewReader(huge), newDuration, protoiter.DelimitedOptions{}) {
+		if !errors.Is(err, protoiter.ErrMessageTooLarge) {
+			t.Errorf("want ErrMessageTooLarge, got %v", err)
+		}
+	}
+	var negative []error
+	for _, err := range protoiter.EachDelimited(bytes.NewReader(huge), newDuration, protoiter.DelimitedOptions{MaxMessageSize: -1}) {
+		negative = append(negative, err)
+	}
+	if len(negative) != 1 || !errors.Is(negative[0], protoiter.ErrMessageTooLarge) {
+		t.Errorf("a negative limit must fall back to the default: %v", negative)
+	}
+	for _, err := range protoiter.EachDelimited(bytes.NewReader(huge), newDuration, protoiter.DelimitedOptions{SkipOversized: true}) {
+		if errors.Is(err, protoiter.ErrMessageTooLarge) {
+			continue
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("skipping past the end must be unexpected EOF, got %v", err)
+		}
+	}
+
+	truncated := stream[:len(stream)-1]
+	var last error
+	for _, err := range protoiter.EachDelimited(bytes.NewReader(truncated), newDuration, protoiter.DelimitedOptions{}) {
+		last = err
+	}
+	if !errors.Is(last, io.ErrUnexpectedEOF) {
+		t.Errorf("a truncated record must be unexpected EOF, got %v", last)
+	}
+
+	bad := append([]byte{1, 0xff}, delimited(durationpb.New(1))...)
+	var got []error
+	for d, err := range protoiter.EachDelimited(bytes.NewReader(bad), newDuration, protoiter.DelimitedOptions{}) {
+		if d == nil {
+			t.Error("a message must be yielded for every complete record")
+		}
+		got = append(got, err)
+	}
+	if len(got) != 2 || got[0] == nil || got[1] != nil {
+		t.Errorf("an invalid record must not stop the iteration: %v", got)
+	}
+
+	for range protoiter.EachDelimited(bytes.NewReader(stream), newDuration, protoiter.DelimitedOptions{}) {
+		break
+	}
+}
+
+func ExampleEachDelimitedIndexed() {
+	stream := delimited(durationpb.New(1), durationpb.New(1000), durationpb.New(1000000))
+	records, err := protoiter.EachDelimitedIndexed(bytes.NewReader(stream), newDuration, protoiter.DelimitedOptions{})
+	for offset, d := range records {
+		fmt.Println(offset, d.AsDuration())
+	}
+	fmt.Println(err())
+	// Output:
+	// 0 1ns
+	// 3 1µs
+	// 7 1ms
+	// <nil>
+}
+
+func TestEachDelimitedIndexed(t *testing.T) {
+	stream := delimited(durationpb.New(1), wrapperspb.String("a very long payload"), durationpb.New(3))
+	r := bytes.NewReader(append([]byte("header"), stream...))
+	r.Seek(6, io.SeekStart)
+	records, errFn := protoiter.EachDelimitedIndexed(r, newDuration, protoiter.DelimitedOptions{MaxMessageSize: 16, SkipOversized: true})
+	var offsets []int64
+	for offset := range records {
+		offsets = append(offsets, offset)
+	}
+	if fmt.Sprint(offsets) != "[6 31]" || errFn() != nil {
+		t.Errorf("offsets %v, error %v", offsets, errFn())
+	}
+
+	records, errFn = protoiter.EachDelimitedIndexed(bytes.NewReader(stream[:len(stream)-1]), newDuration, protoiter.DelimitedOptions{})
+	for range records {
+	}
+	if !errors.Is(errFn(), io.ErrUnexpectedEOF) {
+		t.Errorf("want unexpected EOF, got %v", errFn())
+	}
+	for range records {
+		break
+	}
+}
+
+func TestResumeAt(t *testing.T) {
+	stream := delimited(durationpb.New(1), durationpb.New(2), durationpb.New(3))
+	r := bytes.NewReader(stream)
+	records, _ := protoiter.EachDelimitedIndexed(r, newDuration, protoiter.DelimitedOptions{})
+	var checkpoint int64
+	for offset := range records {
+		checkpoint = offset
+		break
+	}
+	// Resume after the first record, as if the process had crashed after handling it.
+	records, errFn := protoiter.ResumeAt(r, checkpoint+3, newDuration, protoiter.DelimitedOptions{})
+	var got []int64
+	for offset, d := range records {
+		got = append(got, offset, int64(d.AsDuration()))
+	}
+	if fmt.Sprint(got) != "[3 2 6 3]" || errFn() != nil {
+		t.Errorf("resumed %v, error %v", got, errFn())
+	}
+	records, errFn = protoiter.ResumeAt(r, -1, newDuration, protoiter.DelimitedOptions{})
+	for range records {
+		t.Error("a negative offset must not yield")
+	}
+	if errFn() == nil {
+		t.Error("a negative offset must be an error")
+	}
+}
+
+func TestEachDelimitedPool(t *testing.T) {
+	md := (&durationpb.Duration{}).ProtoReflect().Descriptor()
+	created := 0
+	pool := &sync.Pool{New: func() any {
+		created++
+		return dynamicpb.NewMessage(md)
+	}}
+	newM := func() *dynamicpb.Message {
+		t.Error("newM must not be called while the pool provides messages")
+		return dynamicpb.NewMessage(md)
+	}
+	var messages []proto.Message
+	for i := range 100 {
+		messages = append(messages, durationpb.New(time.Duration(i+1)))
+	}
+	stream := delimited(messages...)
+	sum := int64(0)
+	for m, err := range protoiter.EachDelimited(bytes.NewReader(stream), newM, protoiter.DelimitedOptions{Pool: pool}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum += m.Get(md.Fields().ByName("nanos")).Int()
+	}
+	if sum != 5050 {
+		t.Errorf("sum %d", sum)
+	}
+	if created >= 100 {
+		t.Errorf("messages were not reused: %d created", created)
+	}
+}
+
+func TestEachDelimitedRecord(t *testing.T) {
+	stream := delimited(wrapperspb.String("one"), wrapperspb.String("a very long payload"), wrapperspb.String("two"))
+	opts := protoiter.DelimitedOptions{MaxMessageSize: 16, SkipOversized: true}
+	var kept [][]byte
+	oversized := 0
+	for record, err := range protoiter.EachDelimitedRecord(bytes.NewReader(stream), opts) {
+		if errors.Is(err, protoiter.ErrMessageTooLarge) {
+			oversized++
+			continue
+		}
+		kept = append(kept, record)
+	}
+	if oversized != 1 || len(kept) != 2 || string(kept[0][2:]) != "one" || string(kept[1][2:]) != "two" {
+		t.Errorf("records %q, %d oversized", kept, oversized)
+	}
+
+	opts.ZeroCopy = true
+	var first []byte
+	for record, err := range protoiter.EachDelimitedRecord(bytes.NewReader(stream), opts) {
+		if err != nil {
+			continue
+		}
+		if first == nil {
+			first = record
+		} else if &record[0] != &first[0] {
+			t.Error("zero-copy records must share the read buffer")
+		}
+	}
+	for range protoiter.EachDelimitedRecord(bytes.NewReader(stream), protoiter.DelimitedOptions{}) {
+		break
+	}
+}
@@ -0,0 +1,84 @@
+package protoiter
+
+import (
+	"iter"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// FileSummary holds the inventory of a file: what it declares and how it is configured.
+type FileSummary struct {
+	// Path is the path of the file, e.g. "acme/store.proto".
+	Path string
+
+	// Package is the package of the file.
+	Package protoreflect.FullName
+
+	// Messages is the number of messages declared in the file, including nested messages but not map entries.
+	Messages int
+
+	// Enums is the number of enums declared in the file, including nested enums.
+	Enums int
+
+	// Services is the number of services declared in the file.
+	Services int
+
+	// Extensions is the number of extensions declared in the file, including those nested in messages.
+	Extensions int
+
+	// GoPackage is the go_package file option, or "" if it is not set.
+	GoPackage string
+
+	// Deprecated reports whether the file has the deprecated option set.
+	Deprecated bool
+
+	// Descriptor is the descriptor of the file.
+	Descriptor protoreflect.FileDescriptor
+}
+
+// EachFileSummary creates a sequential iterator over summaries of the files in a registry.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields a summary of each file
+func EachFileSummary(files Files) iter.Seq[FileSummary] {
+	return func(yield func(FileSummary) bool) {
+		for fd := range EachFile(files) {
+			if !yield(summarize(fd)) {
+				return
+			}
+		}
+	}
+}
+
+func summarize(fd protoreflect.FileDescriptor) FileSummary {
+	s := FileSummary{
+		Path:       fd.Path(),
+		Package:    fd.Package(),
+		Services:   fd.Services().Len(),
+		Deprecated: isDeprecated(fd),
+		Descriptor: fd,
+	}
+	if options, ok := fd.Options().(*descriptorpb.FileOptions); ok {
+		s.GoPackage = options.GetGoPackage()
+	}
+	walk(fd, func(d protoreflect.Descriptor) bool {
+		switch d := d.(type) {
+		case protoreflect.MessageDescriptor:
+			if !d.IsMapEntry() {
+				s.Messages++
+			}
+		case protoreflect.EnumDescriptor:
+			s.Enums++
+		case protoreflect.FieldDescriptor:
+			if d.IsExtension() {
+				s.Extensions++
+			}
+		}
+		return true
+	})
+	return s
+}
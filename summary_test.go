@@ -0,0 +1,54 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+)
+
+func ExampleEachFileSummary() {
+	for s := range protoiter.EachFileSummary(newFiles(acmeProto)) {
+		fmt.Printf("%s: %d messages, %d enums, %d services, %d extensions\n", s.Path, s.Messages, s.Enums, s.Services, s.Extensions)
+	}
+	// Output:
+	// acme/store.proto: 2 messages, 1 enums, 1 services, 0 extensions
+}
+
+func TestEachFileSummary(t *testing.T) {
+	files := newFiles(extProto, `
+		name: "legacy.proto"
+		package: "test.legacy"
+		options { go_package: "example.com/legacy" deprecated: true }
+		message_type {
+			name: "M"
+			field { name: "m" number: 1 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".test.legacy.M.MEntry" }
+			nested_type {
+				name: "MEntry"
+				field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+				field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING }
+				options { map_entry: true }
+			}
+			enum_type { name: "E" value { name: "E_UNSPECIFIED" number: 0 } }
+			extension { name: "x" number: 150 label: LABEL_OPTIONAL type: TYPE_INT32 extendee: ".test.Base" }
+		}
+		dependency: "ext.proto"
+	`)
+	got := make(map[string]protoiter.FileSummary)
+	for s := range protoiter.EachFileSummary(files) {
+		got[s.Path] = s
+	}
+	if s := got["ext.proto"]; s.Messages != 1 || s.Extensions != 2 || s.Deprecated || s.GoPackage != "" {
+		t.Errorf("ext.proto: %+v", s)
+	}
+	s := got["legacy.proto"]
+	if s.Messages != 1 || s.Enums != 1 || s.Extensions != 1 || s.Package != "test.legacy" {
+		t.Errorf("legacy.proto: %+v", s)
+	}
+	if !s.Deprecated || s.GoPackage != "example.com/legacy" {
+		t.Errorf("legacy.proto options: %+v", s)
+	}
+	for range protoiter.EachFileSummary(files) {
+		break
+	}
+}
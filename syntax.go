@@ -0,0 +1,67 @@
+package protoiter
+
+import (
+	"iter"
+	"sync"
+
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// EachFileBySyntax creates a sequential iterator over the file descriptors declared with a specific syntax.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//   - syntax: The syntax to filter file descriptors (proto2, proto3 or editions)
+//
+// Returns:
+//   - An iterator sequence that yields file descriptors with the specified syntax
+func EachFileBySyntax(files Files, syntax protoreflect.Syntax) iter.Seq[protoreflect.FileDescriptor] {
+	return func(yield func(protoreflect.FileDescriptor) bool) {
+		files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			if fd.Syntax() != syntax {
+				return true
+			}
+			return yield(fd)
+		})
+	}
+}
+
+// EachFileEdition creates a sequential iterator over all file descriptors together with their edition.
+//
+// Files declared with proto2 or proto3 syntax report [descriptorpb.Edition_EDITION_PROTO2] or [descriptorpb.Edition_EDITION_PROTO3],
+// so the key alone tells which files have moved to editions.
+//
+// Parameters:
+//   - files: A Files implementation providing access to file descriptors
+//
+// Returns:
+//   - An iterator sequence that yields the edition and descriptor of each file
+func EachFileEdition(files Files) iter.Seq2[descriptorpb.Edition, protoreflect.FileDescriptor] {
+	return func(yield func(descriptorpb.Edition, protoreflect.FileDescriptor) bool) {
+		files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			return yield(fileEdition(fd), fd)
+		})
+	}
+}
+
+// fileEditions caches the edition of each file declared with editions syntax,
+// which is costly to read because the whole file has to be converted.
+var fileEditions sync.Map // protoreflect.FileDescriptor -> descriptorpb.Edition
+
+func fileEdition(fd protoreflect.FileDescriptor) descriptorpb.Edition {
+	switch fd.Syntax() {
+	case protoreflect.Proto2:
+		return descriptorpb.Edition_EDITION_PROTO2
+	case protoreflect.Proto3:
+		return descriptorpb.Edition_EDITION_PROTO3
+	}
+	if edition, ok := fileEditions.Load(fd); ok {
+		return edition.(descriptorpb.Edition)
+	}
+	// protoreflect does not expose the edition of a file, but protodesc records it in the converted proto.
+	edition := protodesc.ToFileDescriptorProto(fd).GetEdition()
+	fileEditions.Store(fd, edition)
+	return edition
+}
@@ -0,0 +1,48 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"maps"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func ExampleEachFileBySyntax() {
+	files := newFiles(`name: "a.proto" syntax: "proto3"`, `name: "b.proto" syntax: "proto2"`)
+	for file := range protoiter.EachFileBySyntax(files, protoreflect.Proto3) {
+		fmt.Println(file.Path())
+	}
+	// Output:
+	// a.proto
+}
+
+func TestEachFileEdition(t *testing.T) {
+	files := newFiles(
+		`name: "a.proto" syntax: "proto3"`,
+		`name: "b.proto" syntax: "proto2"`,
+		`name: "c.proto" syntax: "editions" edition: EDITION_2023`,
+	)
+	got := make(map[string]descriptorpb.Edition)
+	for edition, file := range protoiter.EachFileEdition(files) {
+		got[file.Path()] = edition
+	}
+	want := map[string]descriptorpb.Edition{
+		"a.proto": descriptorpb.Edition_EDITION_PROTO3,
+		"b.proto": descriptorpb.Edition_EDITION_PROTO2,
+		"c.proto": descriptorpb.Edition_EDITION_2023,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	n := 0
+	for range protoiter.EachFileBySyntax(files, protoreflect.Editions) {
+		n++
+	}
+	if n != 1 {
+		t.Errorf("must yield one editions file, got %d", n)
+	}
+}
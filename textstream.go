@@ -0,0 +1,105 @@
+package protoiter
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// errEmptySeparator reports an empty document separator, which cannot delimit documents.
+var errEmptySeparator = errors.New("protoiter: empty text stream separator")
+
+// WriteTextprotoStream writes a sequence of messages to w as a multi-document text-format stream.
+//
+// Each message is marshaled with opts and followed by sep, e.g. "\n---\n",
+// so the stream can be read back with [EachTextprotoStream] using the same separator.
+// sep must be non-empty and must not occur in the text of a message.
+// The messages are written as they are yielded, without buffering the sequence.
+//
+// Parameters:
+//   - w: The writer receiving the stream
+//   - seq: The messages to write
+//   - sep: The separator written after each document
+//   - opts: The text-format marshaling options, e.g. with Multiline set
+//
+// Returns:
+//   - The first marshaling or write error, if any
+func WriteTextprotoStream(w io.Writer, seq iter.Seq[proto.Message], sep string, opts prototext.MarshalOptions) error {
+	if sep == "" {
+		return errEmptySeparator
+	}
+	var buf []byte
+	for m := range seq {
+		var err error
+		buf, err = opts.MarshalAppend(buf[:0], m)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, sep...)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EachTextprotoStream creates a sequential iterator over the messages of a multi-document text-format stream,
+// as written by [WriteTextprotoStream].
+//
+// Every occurrence of sep ends a document; text after the last separator is a final document if it is not empty.
+// Each document is parsed with opts into a message created by newM. If a document cannot be parsed,
+// the message and an error naming the document index are yielded and iteration continues.
+// A read error, or a document larger than [DefaultMaxMessageSize], is yielded with a zero message and ends the iteration.
+//
+// Parameters:
+//   - r: The stream to read
+//   - sep: The separator ending each document
+//   - newM: A function returning a new empty message for each document
+//   - opts: The text-format unmarshaling options
+//
+// Returns:
+//   - An iterator sequence that yields each parsed message, or an error
+func EachTextprotoStream[M proto.Message](r io.Reader, sep string, newM func() M, opts prototext.UnmarshalOptions) iter.Seq2[M, error] {
+	return func(yield func(M, error) bool) {
+		var zero M
+		if sep == "" {
+			yield(zero, errEmptySeparator)
+			return
+		}
+		sc := bufio.NewScanner(r)
+		sc.Buffer(nil, DefaultMaxMessageSize)
+		sc.Split(splitOn([]byte(sep)))
+		for i := 0; sc.Scan(); i++ {
+			m := newM()
+			err := opts.Unmarshal(sc.Bytes(), m)
+			if err != nil {
+				err = fmt.Errorf("protoiter: document %d: %w", i, err)
+			}
+			if !yield(m, err) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// splitOn returns a split function for [bufio.Scanner] that ends each token at sep.
+func splitOn(sep []byte) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.Index(data, sep); i >= 0 {
+			return i + len(sep), data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
@@ -0,0 +1,72 @@
+package protoiter_test
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func ExampleWriteTextprotoStream() {
+	seq := slices.Values([]proto.Message{durationpb.New(1), &durationpb.Duration{}, &durationpb.Duration{Seconds: 2, Nanos: 3}})
+	var buf bytes.Buffer
+	results.Must(protoiter.WriteTextprotoStream(&buf, seq, "---\n", prototext.MarshalOptions{Multiline: true}))
+	fmt.Print(strings.ReplaceAll(buf.String(), "  ", " "))
+	// Output:
+	// nanos: 1
+	// ---
+	// ---
+	// seconds: 2
+	// nanos: 3
+	// ---
+}
+
+func TestEachTextprotoStream(t *testing.T) {
+	want := []*durationpb.Duration{durationpb.New(1), {}, {Seconds: 2, Nanos: 3}}
+	var buf bytes.Buffer
+	seq := func(yield func(proto.Message) bool) {
+		for _, m := range want {
+			if !yield(m) {
+				return
+			}
+		}
+	}
+	results.Must(protoiter.WriteTextprotoStream(&buf, seq, "\n#---\n", prototext.MarshalOptions{}))
+	var got []*durationpb.Duration
+	for m, err := range protoiter.EachTextprotoStream(&buf, "\n#---\n", newDuration, prototext.UnmarshalOptions{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, m)
+	}
+	if !slices.EqualFunc(got, want, func(a, b *durationpb.Duration) bool { return proto.Equal(a, b) }) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	var errs []error
+	for _, err := range protoiter.EachTextprotoStream(strings.NewReader("seconds: 1;hours: 2;seconds: 3"), ";", newDuration, prototext.UnmarshalOptions{}) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 3 || errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Errorf("unexpected errors %v", errs)
+	}
+
+	if err := protoiter.WriteTextprotoStream(&buf, seq, "", prototext.MarshalOptions{}); err == nil {
+		t.Error("an empty separator must be rejected")
+	}
+	for _, err := range protoiter.EachTextprotoStream(&buf, "", newDuration, prototext.UnmarshalOptions{}) {
+		if err == nil {
+			t.Error("an empty separator must be rejected")
+		}
+	}
+	if err := protoiter.WriteTextprotoStream(failingWriter{}, seq, ";", prototext.MarshalOptions{}); err == nil {
+		t.Error("a write error must be returned")
+	}
+}
@@ -0,0 +1,153 @@
+package protoiter
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Predicate reports whether a descriptor is selected, as used by [ViewOptions].
+type Predicate func(d protoreflect.Descriptor) bool
+
+// InPackage returns a predicate selecting the descriptors declared in the packages matching a glob pattern,
+// as described for [EachMatching]; e.g. "acme.**" selects package acme and its subpackages.
+func InPackage(pattern string) Predicate {
+	return func(d protoreflect.Descriptor) bool {
+		return matchGlob(pattern, string(d.ParentFile().Package()), '.')
+	}
+}
+
+// NameMatches returns a predicate selecting the descriptors whose full name matches a glob pattern,
+// as described for [EachMatching]. A file is matched by its path instead, with "*" not crossing a "/".
+func NameMatches(pattern string) Predicate {
+	return func(d protoreflect.Descriptor) bool {
+		if fd, ok := d.(protoreflect.FileDescriptor); ok {
+			return matchGlob(pattern, fd.Path(), '/')
+		}
+		return matchGlob(pattern, string(d.FullName()), '.')
+	}
+}
+
+// HasOption returns a predicate selecting the descriptors whose options set a custom option,
+// whether or not the options were parsed with the extension known, as for [EachMessageWithOption].
+func HasOption(xt protoreflect.ExtensionType) Predicate {
+	return func(d protoreflect.Descriptor) bool {
+		_, ok := optionValue(d.Options(), xt)
+		return ok
+	}
+}
+
+// ViewOptions selects the contents of a [View].
+type ViewOptions struct {
+	// Include selects the descriptors matching any of the predicates.
+	// If it is empty, every descriptor is included.
+	Include []Predicate
+
+	// Exclude drops the included descriptors matching any of the predicates.
+	Exclude []Predicate
+}
+
+func (opts ViewOptions) match(d protoreflect.Descriptor) bool {
+	included := len(opts.Include) == 0
+	for _, p := range opts.Include {
+		if p(d) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, p := range opts.Exclude {
+		if p(d) {
+			return false
+		}
+	}
+	return true
+}
+
+// View is a filtered subset of a file registry and a type registry.
+//
+// It implements [Files] and [Types] itself, so the subset can be handed to any code expecting a full registry,
+// including the iterators of this package. Files are selected by applying the predicates to each file descriptor,
+// and types by applying them to the descriptor of each type. The view reads through to its sources on every call,
+// so it reflects later registrations.
+type View struct {
+	files Files
+	types Types
+	opts  ViewOptions
+}
+
+var (
+	_ Files = (*View)(nil)
+	_ Types = (*View)(nil)
+)
+
+// NewView returns a view of the files and types selected by opts.
+//
+// Parameters:
+//   - files: The file registry to filter, or nil for a view without files
+//   - types: The type registry to filter, or nil for a view without types
+//   - opts: The predicates selecting the contents of the view
+//
+// Returns:
+//   - The view
+func NewView(files Files, types Types, opts ViewOptions) *View {
+	return &View{files: files, types: types, opts: opts}
+}
+
+// RangeFiles iterates over the selected files while f returns true.
+func (v *View) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	if v.files != nil {
+		v.files.RangeFiles(v.filterFiles(f))
+	}
+}
+
+// RangeFilesByPackage iterates over the selected files of a package while f returns true.
+func (v *View) RangeFilesByPackage(name protoreflect.FullName, f func(protoreflect.FileDescriptor) bool) {
+	if v.files != nil {
+		v.files.RangeFilesByPackage(name, v.filterFiles(f))
+	}
+}
+
+func (v *View) filterFiles(f func(protoreflect.FileDescriptor) bool) func(protoreflect.FileDescriptor) bool {
+	return func(fd protoreflect.FileDescriptor) bool {
+		return !v.opts.match(fd) || f(fd)
+	}
+}
+
+// RangeEnums iterates over the selected enums while f returns true.
+func (v *View) RangeEnums(f func(protoreflect.EnumType) bool) {
+	if v.types != nil {
+		v.types.RangeEnums(func(et protoreflect.EnumType) bool {
+			return !v.opts.match(et.Descriptor()) || f(et)
+		})
+	}
+}
+
+// RangeMessages iterates over the selected messages while f returns true.
+func (v *View) RangeMessages(f func(protoreflect.MessageType) bool) {
+	if v.types != nil {
+		v.types.RangeMessages(func(mt protoreflect.MessageType) bool {
+			return !v.opts.match(mt.Descriptor()) || f(mt)
+		})
+	}
+}
+
+// RangeExtensions iterates over the selected extensions while f returns true.
+func (v *View) RangeExtensions(f func(protoreflect.ExtensionType) bool) {
+	if v.types != nil {
+		v.types.RangeExtensions(v.filterExtensions(f))
+	}
+}
+
+// RangeExtensionsByMessage iterates over the selected extensions of a message while f returns true.
+func (v *View) RangeExtensionsByMessage(message protoreflect.FullName, f func(protoreflect.ExtensionType) bool) {
+	if v.types != nil {
+		v.types.RangeExtensionsByMessage(message, v.filterExtensions(f))
+	}
+}
+
+func (v *View) filterExtensions(f func(protoreflect.ExtensionType) bool) func(protoreflect.ExtensionType) bool {
+	return func(xt protoreflect.ExtensionType) bool {
+		return !v.opts.match(xt.TypeDescriptor()) || f(xt)
+	}
+}
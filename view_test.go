@@ -0,0 +1,79 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func ExampleNewView() {
+	files := newFiles(extProto, acmeProto)
+	view := protoiter.NewView(files, nil, protoiter.ViewOptions{
+		Include: []protoiter.Predicate{protoiter.InPackage("acme.**")},
+	})
+	for d := range protoiter.EachQuery(view, "message") {
+		fmt.Println(d.FullName())
+	}
+	// Output:
+	// acme.store.Blob
+	// acme.store.Blob.Meta
+}
+
+func TestView(t *testing.T) {
+	files := newFiles(optionProto, modelProto, acmeProto, extProto)
+	types := new(protoregistry.Types)
+	for d := range protoiter.EachQuery(files, "message") {
+		results.Must(types.RegisterMessage(dynamicpb.NewMessageType(d.(protoreflect.MessageDescriptor))))
+	}
+	for _, name := range []protoreflect.FullName{"test.a", "test.b", "opt.resource"} {
+		xd := results.Must1(files.FindDescriptorByName(name)).(protoreflect.ExtensionDescriptor)
+		results.Must(types.RegisterExtension(dynamicpb.NewExtensionType(xd)))
+	}
+
+	view := protoiter.NewView(files, types, protoiter.ViewOptions{
+		Include: []protoiter.Predicate{protoiter.InPackage("model"), protoiter.InPackage("test")},
+		Exclude: []protoiter.Predicate{protoiter.NameMatches("test.b")},
+	})
+	var paths []string
+	for fd := range protoiter.EachFile(view) {
+		paths = append(paths, fd.Path())
+	}
+	slices.Sort(paths)
+	if want := []string{"ext.proto", "model.proto"}; !slices.Equal(paths, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", paths, want)
+	}
+	for range protoiter.EachFileByPackage(view, "acme.store") {
+		t.Error("acme.store must not be in the view")
+	}
+	var extensions []protoreflect.FullName
+	for xt := range protoiter.EachExtensionByMessage(view, "test.Base") {
+		extensions = append(extensions, xt.TypeDescriptor().FullName())
+	}
+	if want := []protoreflect.FullName{"test.a"}; !slices.Equal(extensions, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", extensions, want)
+	}
+
+	resources := protoiter.NewView(nil, types, protoiter.ViewOptions{
+		Include: []protoiter.Predicate{protoiter.HasOption(optionType("opt.resource"))},
+	})
+	var messages []protoreflect.FullName
+	for mt := range protoiter.EachMessage(resources) {
+		messages = append(messages, mt.Descriptor().FullName())
+	}
+	slices.Sort(messages)
+	if want := []protoreflect.FullName{"model.Book", "model.Book.Page"}; !slices.Equal(messages, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", messages, want)
+	}
+	for range protoiter.EachFile(resources) {
+		t.Error("a view without files must be empty")
+	}
+	for range protoiter.EachEnum(resources) {
+		t.Error("no enum has the option")
+	}
+}
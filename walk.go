@@ -0,0 +1,74 @@
+package protoiter
+
+import (
+	"iter"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Walk creates a sequential iterator over a descriptor and every descriptor declared within it, recursively.
+//
+// The descriptors are visited depth-first in declaration order, each before the descriptors nested within it,
+// which replaces the nested loops over messages, fields, enums and services otherwise needed to visit a whole file.
+// The children of a file are its messages, enums, extensions and services; those of a message are its fields,
+// oneofs, nested messages, nested enums and nested extensions; an enum has its values and a service its methods.
+// Synthetic map entry messages and oneofs are visited like any other declaration.
+// A field is visited within its message only; the members of a oneof are not visited again under the oneof.
+//
+// Parameters:
+//   - root: The descriptor to start from, typically a file or a message
+//
+// Returns:
+//   - An iterator sequence that yields root and each descriptor declared within it
+func Walk(root protoreflect.Descriptor) iter.Seq[protoreflect.Descriptor] {
+	return func(yield func(protoreflect.Descriptor) bool) {
+		walk(root, yield)
+	}
+}
+
+// eachChild calls yield for every descriptor declared directly within d, in declaration order.
+// It returns false if yield returned false.
+func eachChild(d protoreflect.Descriptor, yield func(protoreflect.Descriptor) bool) bool {
+	switch d := d.(type) {
+	case protoreflect.FileDescriptor:
+		return eachOf(d.Messages(), yield) &&
+			eachOf(d.Enums(), yield) &&
+			eachOf(d.Extensions(), yield) &&
+			eachOf(d.Services(), yield)
+	case protoreflect.MessageDescriptor:
+		return eachOf(d.Fields(), yield) &&
+			eachOf(d.Oneofs(), yield) &&
+			eachOf(d.Messages(), yield) &&
+			eachOf(d.Enums(), yield) &&
+			eachOf(d.Extensions(), yield)
+	case protoreflect.EnumDescriptor:
+		return eachOf(d.Values(), yield)
+	case protoreflect.ServiceDescriptor:
+		return eachOf(d.Methods(), yield)
+	}
+	return true
+}
+
+func eachOf[D protoreflect.Descriptor](dd Descriptors[D], yield func(protoreflect.Descriptor) bool) bool {
+	for i := range dd.Len() {
+		if !yield(dd.Get(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// walk calls yield for d and then for every descriptor declared within it, depth-first in declaration order.
+// It returns false if yield returned false.
+func walk(d protoreflect.Descriptor, yield func(protoreflect.Descriptor) bool) bool {
+	return yield(d) && eachChild(d, func(d protoreflect.Descriptor) bool {
+		return walk(d, yield)
+	})
+}
+
+// walkFiles calls yield for every file in files and every descriptor declared within it.
+func walkFiles(files Files, yield func(protoreflect.Descriptor) bool) {
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		return walk(fd, yield)
+	})
+}
@@ -0,0 +1,79 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func ExampleWalk() {
+	fd := results.Must1(newFiles(acmeProto).FindFileByPath("acme/store.proto"))
+	for d := range protoiter.Walk(fd) {
+		if _, ok := d.(protoreflect.FieldDescriptor); ok {
+			fmt.Println(d.FullName())
+		}
+	}
+	// Output:
+	// acme.store.Blob.id
+	// acme.store.Blob.data
+	// acme.store.Blob.parts
+	// acme.store.Blob.Meta.digest
+	// acme.store.Blob.Meta.state
+}
+
+func TestWalk(t *testing.T) {
+	files := newFiles(`
+		name: "walk.proto"
+		package: "test"
+		message_type {
+			name: "M"
+			field { name: "a" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 oneof_index: 0 }
+			oneof_decl { name: "o" }
+			nested_type { name: "N" field { name: "b" number: 1 label: LABEL_OPTIONAL type: TYPE_INT32 } }
+			enum_type { name: "E" value { name: "E_ZERO" number: 0 } }
+			extension_range { start: 100 end: 200 }
+			extension { name: "x" number: 100 label: LABEL_OPTIONAL type: TYPE_INT32 extendee: ".test.M" }
+		}
+		enum_type { name: "F" value { name: "F_ZERO" number: 0 } }
+		extension { name: "y" number: 101 label: LABEL_OPTIONAL type: TYPE_INT32 extendee: ".test.M" }
+		service {
+			name: "S"
+			method { name: "Get" input_type: ".test.M" output_type: ".test.M" }
+		}
+	`)
+	fd := results.Must1(files.FindFileByPath("walk.proto"))
+	var got []protoreflect.FullName
+	for d := range protoiter.Walk(fd) {
+		got = append(got, d.FullName())
+	}
+	want := []protoreflect.FullName{
+		"test", // The full name of a file is its package.
+		"test.M", "test.M.a", "test.M.o", "test.M.N", "test.M.N.b", "test.M.E", "test.M.E_ZERO", "test.M.x",
+		"test.F", "test.F_ZERO",
+		"test.y",
+		"test.S", "test.S.Get",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	md := results.Must1(files.FindDescriptorByName("test.M.N"))
+	got = nil
+	for d := range protoiter.Walk(md) {
+		got = append(got, d.FullName())
+	}
+	if want := []protoreflect.FullName{"test.M.N", "test.M.N.b"}; !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%v\nwant\t%v", got, want)
+	}
+
+	for d := range protoiter.Walk(fd) {
+		if d != fd {
+			t.Errorf("iteration must stop at the root, got %v", d.FullName())
+		}
+		break
+	}
+}
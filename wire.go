@@ -0,0 +1,84 @@
+package protoiter
+
+import (
+	"bytes"
+	"iter"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// WireField is a field decoded from the protocol buffer wire format.
+type WireField struct {
+	// Number is the field number of the tag.
+	Number protowire.Number
+
+	// Type is the wire type of the tag.
+	Type protowire.Type
+
+	// Value is the encoded value without the tag:
+	// the varint or fixed-size bytes, the payload of a length-delimited field without its length prefix,
+	// or the contents of a group without its end tag.
+	Value []byte
+
+	// Size is the encoded size of the whole field, including its tag.
+	Size int
+}
+
+// EachWireField creates a sequential iterator over the fields encoded in a wire-format message.
+//
+// The fields are yielded in encoding order without consulting any descriptor, so unknown and repeated fields appear as they are encoded.
+// Each Value is a copy that the caller may retain, unless opts.ZeroCopy is set.
+// If the input is malformed, the iterator yields a zero WireField with the error and stops.
+//
+// Parameters:
+//   - b: The wire-format bytes of a message
+//   - opts: The options controlling copying
+//
+// Returns:
+//   - An iterator sequence that yields each field, or an error
+func EachWireField(b []byte, opts WireOptions) iter.Seq2[WireField, error] {
+	return eachWireField(b, !opts.ZeroCopy)
+}
+
+// WireOptions configures [EachWireField].
+//
+// The zero value yields a copy of each value.
+type WireOptions struct {
+	// ZeroCopy makes each Value a sub-slice of the input instead of a copy, avoiding an allocation per field
+	// for scanning jobs that only inspect and discard the values.
+	// Such a value must not be modified, and it changes if the input is modified or reused,
+	// so a value that must outlive the contents of the input has to be copied.
+	ZeroCopy bool
+}
+
+func eachWireField(b []byte, clone bool) iter.Seq2[WireField, error] {
+	return func(yield func(WireField, error) bool) {
+		for len(b) > 0 {
+			num, typ, n := protowire.ConsumeTag(b)
+			if n < 0 {
+				yield(WireField{}, protowire.ParseError(n))
+				return
+			}
+			m := protowire.ConsumeFieldValue(num, typ, b[n:])
+			if m < 0 {
+				yield(WireField{}, protowire.ParseError(m))
+				return
+			}
+			value := b[n : n+m]
+			switch typ {
+			case protowire.BytesType:
+				_, k := protowire.ConsumeVarint(value)
+				value = value[k:]
+			case protowire.StartGroupType:
+				value = value[:len(value)-protowire.SizeTag(num)]
+			}
+			if clone {
+				value = bytes.Clone(value)
+			}
+			if !yield(WireField{Number: num, Type: typ, Value: value, Size: n + m}, nil) {
+				return
+			}
+			b = b[n+m:]
+		}
+	}
+}
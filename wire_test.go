@@ -0,0 +1,86 @@
+package protoiter_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func ExampleEachWireField() {
+	b := results.Must1(proto.Marshal(durationpb.New(3_000_000_500)))
+	for field, err := range protoiter.EachWireField(b, protoiter.WireOptions{}) {
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(field.Number, field.Type, field.Size)
+	}
+	// Output:
+	// 1 0 2
+	// 2 0 3
+}
+
+func TestEachWireField(t *testing.T) {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, "abc")
+	b = protowire.AppendTag(b, 2, protowire.StartGroupType)
+	b = protowire.AppendTag(b, 3, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, 7)
+	b = protowire.AppendTag(b, 2, protowire.EndGroupType)
+
+	var got []protoiter.WireField
+	for field, err := range protoiter.EachWireField(b, protoiter.WireOptions{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, field)
+	}
+	if len(got) != 2 {
+		t.Fatalf("must yield 2 fields, got %d", len(got))
+	}
+	if string(got[0].Value) != "abc" || got[0].Size != 5 {
+		t.Errorf("unexpected bytes field %+v", got[0])
+	}
+	if !bytes.Equal(got[1].Value, []byte{0x1d, 7, 0, 0, 0}) || got[1].Size != 7 {
+		t.Errorf("unexpected group field %+v", got[1])
+	}
+	b[2] = 'x'
+	if string(got[0].Value) != "abc" {
+		t.Error("values must not alias the input")
+	}
+
+	n := 0
+	for _, err := range protoiter.EachWireField(b[:len(b)-1], protoiter.WireOptions{}) {
+		n++
+		if err == nil && n == 2 {
+			t.Error("a truncated group must be an error")
+		}
+	}
+	if n != 2 {
+		t.Errorf("must stop after the error, got %d elements", n)
+	}
+}
+
+func TestEachWireFieldZeroCopy(t *testing.T) {
+	b := results.Must1(proto.Marshal(wrapperspb.String("hello")))
+	for f, err := range protoiter.EachWireField(b, protoiter.WireOptions{ZeroCopy: true}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(f.Value) != "hello" || &f.Value[0] != &b[2] {
+			t.Errorf("value %q must alias the input", f.Value)
+		}
+	}
+	for f := range protoiter.EachWireField(b, protoiter.WireOptions{}) {
+		if &f.Value[0] == &b[2] {
+			t.Error("values must be copied by default")
+		}
+	}
+}
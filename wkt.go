@@ -0,0 +1,176 @@
+package protoiter
+
+import (
+	"iter"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protopath"
+	"google.golang.org/protobuf/reflect/protorange"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// EachWKTField creates a sequential iterator over the populated fields of a message whose type is a well-known type,
+// with each value converted to its natural Go type.
+//
+// The conversions are:
+//   - google.protobuf.Timestamp: [time.Time] in UTC
+//   - google.protobuf.Duration: [time.Duration]
+//   - google.protobuf.Struct: map[string]any
+//   - google.protobuf.Value: any, as returned by [structpb.Value.AsInterface]
+//   - google.protobuf.ListValue: []any
+//   - google.protobuf.FieldMask: []string of the paths
+//   - wrappers such as google.protobuf.Int64Value: the wrapped value, e.g. int64
+//
+// A repeated field yields a []any of the converted elements. Map fields and fields of other types are skipped.
+// Both generated and dynamic messages are converted. A dynamic Struct, Value or ListValue is copied
+// into the generated type through the wire format; if that fails, the field is skipped rather than
+// yielded as an empty value, and so is a repeated field with any element that fails.
+//
+// Parameters:
+//   - message: The protocol buffer message whose fields to iterate
+//
+// Returns:
+//   - An iterator sequence that yields the field descriptor and the converted value
+func EachWKTField(message protoreflect.Message) iter.Seq2[protoreflect.FieldDescriptor, any] {
+	return func(yield func(protoreflect.FieldDescriptor, any) bool) {
+		message.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			if fd.IsMap() || fd.Message() == nil {
+				return true
+			}
+			convert, ok := wktConverters[fd.Message().FullName()]
+			if !ok {
+				return true
+			}
+			if !fd.IsList() {
+				value, ok := convert(v.Message())
+				return !ok || yield(fd, value)
+			}
+			list := v.List()
+			values := make([]any, list.Len())
+			for i := range values {
+				if values[i], ok = convert(list.Get(i).Message()); !ok {
+					return true
+				}
+			}
+			return yield(fd, values)
+		})
+	}
+}
+
+// wktConverters convert a well-known message to its Go value, reporting false if it cannot be converted.
+var wktConverters = map[protoreflect.FullName]func(protoreflect.Message) (any, bool){
+	"google.protobuf.Timestamp": func(m protoreflect.Message) (any, bool) {
+		return time.Unix(wktField(m, 1).Int(), wktField(m, 2).Int()).UTC(), true
+	},
+	"google.protobuf.Duration": func(m protoreflect.Message) (any, bool) {
+		// AsDuration clamps durations beyond the range of time.Duration instead of wrapping.
+		d := &durationpb.Duration{Seconds: wktField(m, 1).Int(), Nanos: int32(wktField(m, 2).Int())}
+		return d.AsDuration(), true
+	},
+	"google.protobuf.Struct": func(m protoreflect.Message) (any, bool) {
+		s, err := asKnown(m, new(structpb.Struct))
+		return s.AsMap(), err == nil
+	},
+	"google.protobuf.Value": func(m protoreflect.Message) (any, bool) {
+		v, err := asKnown(m, new(structpb.Value))
+		return v.AsInterface(), err == nil
+	},
+	"google.protobuf.ListValue": func(m protoreflect.Message) (any, bool) {
+		l, err := asKnown(m, new(structpb.ListValue))
+		return l.AsSlice(), err == nil
+	},
+	"google.protobuf.FieldMask": func(m protoreflect.Message) (any, bool) {
+		list := wktField(m, 1).List()
+		paths := make([]string, list.Len())
+		for i := range paths {
+			paths[i] = list.Get(i).String()
+		}
+		return paths, true
+	},
+	"google.protobuf.DoubleValue": wrappedValue,
+	"google.protobuf.FloatValue":  wrappedValue,
+	"google.protobuf.Int64Value":  wrappedValue,
+	"google.protobuf.UInt64Value": wrappedValue,
+	"google.protobuf.Int32Value":  wrappedValue,
+	"google.protobuf.UInt32Value": wrappedValue,
+	"google.protobuf.BoolValue":   wrappedValue,
+	"google.protobuf.StringValue": wrappedValue,
+	"google.protobuf.BytesValue":  wrappedValue,
+}
+
+// wktField returns the value of the field numbered n.
+func wktField(m protoreflect.Message, n protoreflect.FieldNumber) protoreflect.Value {
+	return m.Get(m.Descriptor().Fields().ByNumber(n))
+}
+
+// wrappedValue returns the value field of a wrapper message.
+func wrappedValue(m protoreflect.Message) (any, bool) {
+	return wktField(m, 1).Interface(), true
+}
+
+// asKnown returns m as the generated type of known, copying through the wire format if m is dynamic.
+// If the copy fails, it returns the error and an empty known.
+func asKnown[M proto.Message](m protoreflect.Message, known M) (M, error) {
+	if v, ok := m.Interface().(M); ok {
+		return v, nil
+	}
+	b, err := proto.Marshal(m.Interface())
+	if err == nil {
+		err = proto.Unmarshal(b, known)
+	}
+	if err != nil {
+		proto.Reset(known)
+	}
+	return known, err
+}
+
+// EachTimestamp creates a sequential iterator over the google.protobuf.Timestamp messages in a message tree,
+// converted to [time.Time] in UTC.
+//
+// Messages, lists and maps are descended into as by [EachPath], so timestamps in nested messages,
+// list elements and map values are found; google.protobuf.Any messages are not expanded.
+// This serves audits such as clock-skew checks over whole payloads.
+//
+// Parameters:
+//   - message: The protocol buffer message to scan
+//
+// Returns:
+//   - An iterator sequence that yields the path and value of each populated timestamp
+func EachTimestamp(message protoreflect.Message) iter.Seq2[protopath.Path, time.Time] {
+	return eachWKT[time.Time](message, "google.protobuf.Timestamp")
+}
+
+// EachDuration creates a sequential iterator over the google.protobuf.Duration messages in a message tree,
+// converted to [time.Duration].
+//
+// It is the duration counterpart of [EachTimestamp], e.g. for scanning TTLs.
+//
+// Parameters:
+//   - message: The protocol buffer message to scan
+//
+// Returns:
+//   - An iterator sequence that yields the path and value of each populated duration
+func EachDuration(message protoreflect.Message) iter.Seq2[protopath.Path, time.Duration] {
+	return eachWKT[time.Duration](message, "google.protobuf.Duration")
+}
+
+func eachWKT[T any](message protoreflect.Message, name protoreflect.FullName) iter.Seq2[protopath.Path, T] {
+	return func(yield func(protopath.Path, T) bool) {
+		convert := wktConverters[name]
+		noAnyExpansion.Range(message, func(p protopath.Values) error {
+			m, ok := p.Index(-1).Value.Interface().(protoreflect.Message)
+			if !ok || m.Descriptor().FullName() != name {
+				return nil
+			}
+			v, ok := convert(m)
+			if ok && !yield(slices.Clone(p.Path), v.(T)) {
+				return protorange.Terminate
+			}
+			return nil
+		}, nil)
+	}
+}
@@ -0,0 +1,204 @@
+package protoiter_test
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/goaux/protoiter"
+	"github.com/goaux/results"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func ExampleEachWKTField() {
+	v := results.Must1(structpb.NewValue(map[string]any{"answer": 42}))
+	for fd, value := range protoiter.EachWKTField(v.ProtoReflect()) {
+		fmt.Println(fd.Name(), value)
+	}
+	// Output:
+	// struct_value map[answer:42]
+}
+
+const wktProto = `
+	name: "wkt.proto"
+	package: "test"
+	dependency: ["google/protobuf/timestamp.proto", "google/protobuf/duration.proto", "google/protobuf/struct.proto", "google/protobuf/field_mask.proto", "google/protobuf/wrappers.proto"]
+	message_type {
+		name: "Event"
+		field { name: "at" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Timestamp" }
+		field { name: "took" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Duration" }
+		field { name: "attrs" number: 3 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Struct" }
+		field { name: "mask" number: 4 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.FieldMask" }
+		field { name: "count" number: 5 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Int64Value" }
+		field { name: "tags" number: 6 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".google.protobuf.StringValue" }
+		field { name: "name" number: 7 label: LABEL_OPTIONAL type: TYPE_STRING }
+		field { name: "unset" number: 8 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Timestamp" }
+	}
+`
+
+func TestEachWKTField(t *testing.T) {
+	var _ fieldmaskpb.FieldMask
+	md := results.Must1(newFiles(wktProto).FindDescriptorByName("test.Event")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	err := prototext.Unmarshal([]byte(`
+		at { seconds: 1700000000 nanos: 5 }
+		took { seconds: 2 nanos: 500000000 }
+		attrs { fields { key: "k" value { bool_value: true } } }
+		mask { paths: ["a.b", "c"] }
+		count { value: 7 }
+		tags { value: "x" } tags { value: "y" }
+		name: "plain"
+	`), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[protoreflect.Name]any)
+	for fd, value := range protoiter.EachWKTField(m) {
+		got[fd.Name()] = value
+	}
+	want := map[protoreflect.Name]any{
+		"at":    time.Unix(1700000000, 5).UTC(),
+		"took":  2500 * time.Millisecond,
+		"attrs": map[string]any{"k": true},
+		"mask":  []string{"a.b", "c"},
+		"count": int64(7),
+		"tags":  []any{"x", "y"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+	for range protoiter.EachWKTField(m) {
+		break
+	}
+}
+
+func TestEachWKTFieldUnconvertible(t *testing.T) {
+	// A look-alike google.protobuf.Value whose string_value holds bytes encodes invalid UTF-8,
+	// which the generated structpb.Value refuses to decode.
+	files := newFiles(`
+		name: "lookalike.proto"
+		package: "google.protobuf"
+		syntax: "proto3"
+		message_type {
+			name: "Value"
+			field { name: "string_value" number: 3 label: LABEL_OPTIONAL type: TYPE_BYTES }
+		}
+	`, `
+		name: "holder.proto"
+		package: "test"
+		dependency: "lookalike.proto"
+		message_type {
+			name: "Holder"
+			field { name: "bad" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Value" }
+			field { name: "bads" number: 2 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".google.protobuf.Value" }
+			field { name: "good" number: 3 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Value" }
+		}
+	`)
+	md := results.Must1(files.FindDescriptorByName("test.Holder")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	results.Must(prototext.Unmarshal([]byte(`
+		bad { string_value: "\xff" }
+		bads { string_value: "ok" } bads { string_value: "\xff" }
+		good { string_value: "fine" }
+	`), m))
+	got := make(map[protoreflect.Name]any)
+	for fd, value := range protoiter.EachWKTField(m) {
+		got[fd.Name()] = value
+	}
+	if want := map[protoreflect.Name]any{"good": "fine"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("values that cannot be converted must be skipped\ngot\t%v\nwant\t%v", got, want)
+	}
+}
+
+const jobProto = `
+	name: "job.proto"
+	package: "test"
+	dependency: ["google/protobuf/timestamp.proto", "google/protobuf/duration.proto"]
+	message_type {
+		name: "Job"
+		field { name: "created" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Timestamp" }
+		field { name: "runs" number: 2 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".google.protobuf.Timestamp" }
+		field { name: "ttl" number: 3 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".test.Job.TtlEntry" }
+		field { name: "child" number: 4 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".test.Job" }
+		nested_type {
+			name: "TtlEntry"
+			field { name: "key" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING }
+			field { name: "value" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".google.protobuf.Duration" }
+			options { map_entry: true }
+		}
+	}
+`
+
+// newJob returns a dynamic test.Job parsed from text format.
+func newJob(text string) protoreflect.Message {
+	md := results.Must1(newFiles(jobProto).FindDescriptorByName("test.Job")).(protoreflect.MessageDescriptor)
+	m := dynamicpb.NewMessage(md)
+	results.Must(prototext.Unmarshal([]byte(text), m))
+	return m
+}
+
+func ExampleEachTimestamp() {
+	m := newJob(`
+		created { seconds: 1700000000 }
+		runs { seconds: 1700000060 } runs { seconds: 1700000120 }
+		child { created { seconds: 1600000000 } }
+	`)
+	for path, ts := range protoiter.EachTimestamp(m) {
+		fmt.Println(path, ts.Format(time.RFC3339))
+	}
+	// Output:
+	// (test.Job).created 2023-11-14T22:13:20Z
+	// (test.Job).runs[0] 2023-11-14T22:14:20Z
+	// (test.Job).runs[1] 2023-11-14T22:15:20Z
+	// (test.Job).child.created 2020-09-13T12:26:40Z
+}
+
+func TestEachDuration(t *testing.T) {
+	m := newJob(`
+		created { seconds: 1 }
+		ttl { key: "b" value { seconds: 60 } }
+		ttl { key: "a" value { nanos: 5 } }
+		child { ttl { key: "c" value { seconds: 1 } } }
+	`)
+	var got []string
+	for path, d := range protoiter.EachDuration(m) {
+		got = append(got, fmt.Sprint(path, " ", d))
+	}
+	want := []string{
+		`(test.Job).ttl["a"] 5ns`,
+		`(test.Job).ttl["b"] 1m0s`,
+		`(test.Job).child.ttl["c"] 1s`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be equal\ngot\t%q\nwant\t%q", got, want)
+	}
+	for range protoiter.EachDuration(m) {
+		break
+	}
+	for range protoiter.EachTimestamp(m.Get(m.Descriptor().Fields().ByName("child")).Message()) {
+		t.Error("child has no timestamps")
+	}
+	// The largest valid durations do not fit in a time.Duration and are clamped.
+	m = newJob(`
+		ttl { key: "max" value { seconds: 315576000000 } }
+		ttl { key: "min" value { seconds: -315576000000 } }
+	`)
+	got = nil
+	for path, d := range protoiter.EachDuration(m) {
+		got = append(got, fmt.Sprint(path, " ", int64(d)))
+	}
+	want = []string{
+		fmt.Sprintf(`(test.Job).ttl["max"] %d`, math.MaxInt64),
+		fmt.Sprintf(`(test.Job).ttl["min"] %d`, math.MinInt64),
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("must be clamped\ngot\t%q\nwant\t%q", got, want)
+	}
+}